@@ -0,0 +1,33 @@
+package tmplfn
+
+import (
+	"reflect"
+	"strings"
+
+	// Caltech Library packages
+	"github.com/caltechlibrary/dotpath"
+)
+
+// pluck returns list unchanged when no dot path is given, otherwise it
+// returns the value of the dot path (e.g. ".citations") in each element
+// of list. Elements without the path are skipped.
+func pluck(list interface{}, paths []string) interface{} {
+	if len(paths) == 0 || paths[0] == "" || list == nil {
+		return list
+	}
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return list
+	}
+	p := paths[0]
+	if strings.HasPrefix(p, ".") == false {
+		p = "." + p
+	}
+	values := []interface{}{}
+	for i := 0; i < rv.Len(); i++ {
+		if v, err := dotpath.Eval(p, rv.Index(i).Interface()); err == nil {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,191 @@
+package tmplfn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// maxDateRange limits the number of dates DateRange will produce
+const maxDateRange = 100000
+
+// CalendarDay is a cell of a MonthGrid
+type CalendarDay struct {
+	// Date is midnight at the start of the day
+	Date time.Time
+	// Day is the day of the month
+	Day int
+	// InMonth is false for the days of the previous (Leading) and next
+	// (Trailing) month that fill out the first and last weeks
+	InMonth  bool
+	Leading  bool
+	Trailing bool
+	// Weekend is true for Saturday and Sunday
+	Weekend bool
+}
+
+// MonthGrid holds the weeks of a month for rendering a calendar table,
+// each week has seven days starting on WeekStart.
+type MonthGrid struct {
+	Year      int
+	Month     time.Month
+	WeekStart time.Weekday
+	Weeks     [][]CalendarDay
+}
+
+// NewMonthGrid returns the MonthGrid for month of year with weeks
+// starting on weekStart
+func NewMonthGrid(year int, month time.Month, weekStart time.Weekday, loc *time.Location) MonthGrid {
+	if loc == nil {
+		loc = time.UTC
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	lead := (int(first.Weekday()) - int(weekStart) + 7) % 7
+	day := first.AddDate(0, 0, -lead)
+	grid := MonthGrid{Year: year, Month: month, WeekStart: weekStart}
+	for len(grid.Weeks) == 0 || day.Month() == month {
+		week := make([]CalendarDay, 7)
+		for i := range week {
+			week[i] = CalendarDay{
+				Date:     day,
+				Day:      day.Day(),
+				InMonth:  day.Month() == month,
+				Leading:  day.Before(first),
+				Trailing: day.Month() != month && day.After(first),
+				Weekend:  day.Weekday() == time.Saturday || day.Weekday() == time.Sunday,
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+		grid.Weeks = append(grid.Weeks, week)
+	}
+	return grid
+}
+
+// parseWeekday reads a weekday name (e.g. "monday", "Mon") or number
+// (0 is Sunday)
+func parseWeekday(s string) (time.Weekday, error) {
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 6 {
+		return time.Weekday(n), nil
+	}
+	name := strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return d, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown weekday %q", s)
+}
+
+// DateRange returns the dates from start to end inclusive, every step.
+// Step is a count and unit (e.g. "1 day", "2 weeks", "month", "3m" is
+// three minutes) or a Go duration (e.g. "36h"). If end is before start
+// the dates are in descending order.
+func DateRange(start, end time.Time, step string) ([]time.Time, error) {
+	n, unit, err := parseStep(step)
+	if err != nil {
+		return nil, err
+	}
+	var result []time.Time
+	descending := end.Before(start)
+	if descending {
+		n = -n
+	}
+	for i := 0; ; i++ {
+		var t time.Time
+		if unit == "" {
+			t = start.Add(time.Duration(i) * time.Duration(n))
+		} else if t, err = dateAdd(start, i*n, unit); err != nil {
+			return nil, err
+		}
+		if descending {
+			if t.Before(end) {
+				break
+			}
+		} else if t.After(end) {
+			break
+		}
+		if i >= maxDateRange {
+			return nil, fmt.Errorf("more than %d dates in range", maxDateRange)
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// parseStep reads a step for DateRange, unit is empty when n is a
+// duration in nanoseconds. The sign of n is ignored, the direction comes
+// from the start and end dates.
+func parseStep(step string) (int, string, error) {
+	s := strings.TrimSpace(step)
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	n := 1
+	if i > 0 {
+		var err error
+		if n, err = strconv.Atoi(s[:i]); err != nil {
+			return 0, "", fmt.Errorf("step %q is too large", step)
+		}
+	}
+	if unit := normalizeUnit(s[i:]); unit != "" {
+		if n == 0 {
+			return 0, "", fmt.Errorf("step %q must be greater than zero", step)
+		}
+		return n, unit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, "", fmt.Errorf("can't read step %q", step)
+	}
+	if d < 0 {
+		d = -d
+	}
+	if d == 0 {
+		return 0, "", fmt.Errorf("step %q must be greater than zero", step)
+	}
+	return int(d), "", nil
+}
+
+// calendarFuncs returns the dates and month_grid functions reading
+// dates with tf
+func (tf *timeFuncs) calendarFuncs() template.FuncMap {
+	return template.FuncMap{
+		// dates returns the dates from start to end inclusive every step (e.g. "1 day",
+		// "2 weeks", "month"), if start is after end the dates are descending.
+		// See DateRange.
+		"dates": func(start, end interface{}, step string) ([]time.Time, error) {
+			s, err := tf.toTime(start)
+			if err != nil {
+				return nil, fmt.Errorf("dates: %s", err)
+			}
+			e, err := tf.toTime(end)
+			if err != nil {
+				return nil, fmt.Errorf("dates: %s", err)
+			}
+			result, err := DateRange(s, e, step)
+			if err != nil {
+				return nil, fmt.Errorf("dates: %s", err)
+			}
+			return result, nil
+		},
+		// month_grid returns a MonthGrid for the month containing v, weeks start on Sunday
+		// unless a weekday (e.g. "monday") is given. Range over .Weeks then over each week's days.
+		"month_grid": func(v interface{}, weekStart ...string) (MonthGrid, error) {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return MonthGrid{}, fmt.Errorf("month_grid: %s", err)
+			}
+			start := time.Sunday
+			if len(weekStart) > 0 {
+				if start, err = parseWeekday(weekStart[0]); err != nil {
+					return MonthGrid{}, fmt.Errorf("month_grid: %s", err)
+				}
+			}
+			return NewMonthGrid(dt.Year(), dt.Month(), start, dt.Location()), nil
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package tmplfn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateRange(t *testing.T) {
+	start := time.Date(2017, 1, 31, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	dates, err := DateRange(start, end, "month")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	expected := []string{"2017-01-31", "2017-02-28", "2017-03-31", "2017-04-30"}
+	if len(dates) != len(expected) {
+		t.Fatalf("expected %d dates, got %d", len(expected), len(dates))
+	}
+	for i, dt := range dates {
+		if r := dt.Format("2006-01-02"); r != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], r)
+		}
+	}
+
+	dates, err = DateRange(end, start, "2 weeks")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(dates) != 7 || dates[1].Format("2006-01-02") != "2017-04-17" {
+		t.Errorf("expected 7 descending dates, got %v", dates)
+	}
+	if dates, err = DateRange(start, start.Add(36*time.Hour), "12h"); err != nil || len(dates) != 4 {
+		t.Errorf("expected 4 dates, got %v, %v", dates, err)
+	}
+	for _, step := range []string{"0 days", "0s", "fortnightly", "99999999999999999999 days"} {
+		if _, err := DateRange(start, end, step); err == nil {
+			t.Errorf("%q, expected an error", step)
+		}
+	}
+}
+
+func TestMonthGrid(t *testing.T) {
+	grid := NewMonthGrid(2017, time.March, time.Monday, nil)
+	if len(grid.Weeks) != 5 {
+		t.Fatalf("expected 5 weeks, got %d", len(grid.Weeks))
+	}
+	first := grid.Weeks[0][0]
+	if first.Day != 27 || first.Leading == false || first.InMonth {
+		t.Errorf("expected leading February 27, got %+v", first)
+	}
+	last := grid.Weeks[4][6]
+	if last.Day != 2 || last.Trailing == false || last.Weekend == false {
+		t.Errorf("expected trailing Sunday April 2, got %+v", last)
+	}
+
+	src := `{{ range (month_grid .month "mon").Weeks }}{{ range . }}{{ if .InMonth }}{{ .Day }}{{ else }}.{{ end }} {{ end }}
+{{ end }}`
+	tmpl, err := assembleString(AllFuncs(), src)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, map[string]interface{}{"month": "2017-02"}); err != nil {
+		t.Fatalf("%s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 || lines[0] != ". . 1 2 3 4 5 " {
+		t.Errorf("unexpected calendar\n%s", buf.String())
+	}
+
+	for _, src := range []string{`{{ month_grid "someday" }}`, `{{ month_grid "2017-02" "funday" }}`, `{{ dates "2017-01-01" "2017-02-01" "1 dya" }}`, `{{ dates "2017-01-01" "soon" "1 day" }}`} {
+		tmpl, err := assembleString(AllFuncs(), src)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if err := tmpl.Execute(bytes.NewBuffer([]byte{}), nil); err == nil {
+			t.Errorf("%s, expected Execute to fail", src)
+		}
+	}
+}
+
+func TestCalendarFuncsClock(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("can't load zone, %s", err)
+	}
+	// 2017-03-01 05:00 UTC is still February 28 in Los Angeles
+	clock := FixedClock(time.Date(2017, 3, 1, 5, 0, 0, 0, time.UTC))
+	fm := Join(AllFuncs(), TimeFuncMap(&TimeOptions{Clock: clock, Location: loc}))
+	src := `{{ range dates "now" "2017-03-02" "1 day" }}{{ timefmt . "Jan 2 MST" }}, {{ end }}{{ with month_grid "now" }}{{ .Month }}{{ end }}`
+	tmpl, err := assembleString(fm, src)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, nil); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "Feb 28 PST, Mar 1 PST, February"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
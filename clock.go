@@ -0,0 +1,75 @@
+package tmplfn
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time used for "now" by the Time functions
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock
+type ClockFunc func() time.Time
+
+// Now returns f()
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock reads the system time, it is the default Clock
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock returns a Clock that always returns t. It is useful for
+// golden file tests of rendered pages.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time {
+		return t
+	})
+}
+
+// frozenClock reads its source once and returns that time afterwards
+type frozenClock struct {
+	once   sync.Once
+	source Clock
+	t      time.Time
+}
+
+func (c *frozenClock) Now() time.Time {
+	c.once.Do(func() {
+		c.t = c.source.Now()
+	})
+	return c.t
+}
+
+// FrozenClock returns a Clock that reads source the first time it is
+// asked and returns that time from then on. Build a FuncMap with a new
+// FrozenClock for each render so every "now" in the render agrees, e.g.
+//
+//	tpl.Funcs(tmplfn.TimeFuncMap(&tmplfn.TimeOptions{Clock: tmplfn.FrozenClock(tmplfn.SystemClock)}))
+//	tpl.Execute(out, data)
+func FrozenClock(source Clock) Clock {
+	return &frozenClock{source: source}
+}
+
+// SourceDateEpochClock returns a FixedClock set to the Unix timestamp
+// in the SOURCE_DATE_EPOCH environment variable (see
+// https://reproducible-builds.org/specs/source-date-epoch/). If the
+// variable is not set SystemClock is returned, if it is not a valid
+// timestamp an error is returned.
+func SourceDateEpochClock() (Clock, error) {
+	s := strings.TrimSpace(os.Getenv("SOURCE_DATE_EPOCH"))
+	if s == "" {
+		return SystemClock, nil
+	}
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("SOURCE_DATE_EPOCH %q, %s", s, err)
+	}
+	return FixedClock(time.Unix(sec, 0).UTC()), nil
+}
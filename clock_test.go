@@ -0,0 +1,61 @@
+package tmplfn
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFixedClock(t *testing.T) {
+	now := time.Date(2017, 3, 16, 14, 22, 5, 0, time.UTC)
+	fm := TimeFuncMap(&TimeOptions{Clock: FixedClock(now)})
+	year := fm["year"].(func(interface{}) string)
+	if r := year("now"); r != "2017" {
+		t.Errorf("expected 2017, got %q", r)
+	}
+	timeAgo := fm["time_ago"].(func(interface{}) string)
+	if r := timeAgo("2017-03-13T14:22:05Z"); r != "3 days ago" {
+		t.Errorf("expected %q, got %q", "3 days ago", r)
+	}
+	timeUntil := fm["time_until"].(func(interface{}) string)
+	if r := timeUntil("2017-03-30T14:22:05Z"); r != "in 2 weeks" {
+		t.Errorf("expected %q, got %q", "in 2 weeks", r)
+	}
+}
+
+func TestFrozenClock(t *testing.T) {
+	calls := 0
+	source := ClockFunc(func() time.Time {
+		calls++
+		return time.Unix(int64(calls), 0)
+	})
+	c := FrozenClock(source)
+	first := c.Now()
+	if second := c.Now(); second.Equal(first) == false || calls != 1 {
+		t.Errorf("expected a frozen time, got %s and %s after %d calls", first, second, calls)
+	}
+}
+
+func TestSourceDateEpochClock(t *testing.T) {
+	saved, wasSet := os.LookupEnv("SOURCE_DATE_EPOCH")
+	defer func() {
+		if wasSet {
+			os.Setenv("SOURCE_DATE_EPOCH", saved)
+		} else {
+			os.Unsetenv("SOURCE_DATE_EPOCH")
+		}
+	}()
+
+	os.Setenv("SOURCE_DATE_EPOCH", "1489674125")
+	c, err := SourceDateEpochClock()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := time.Date(2017, 3, 16, 14, 22, 5, 0, time.UTC); c.Now().Equal(expected) == false {
+		t.Errorf("expected %s, got %s", expected, c.Now())
+	}
+	os.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	if _, err := SourceDateEpochClock(); err == nil {
+		t.Errorf("expected an error for an invalid SOURCE_DATE_EPOCH")
+	}
+}
@@ -0,0 +1,170 @@
+package tmplfn
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// normalizeUnit maps singular, plural and short unit names to a
+// canonical plural name (e.g. "day", "days" and "d" are all "days")
+func normalizeUnit(unit string) string {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "ns", "nanosecond", "nanoseconds":
+		return "nanoseconds"
+	case "ms", "millisecond", "milliseconds":
+		return "milliseconds"
+	case "s", "sec", "second", "seconds":
+		return "seconds"
+	case "m", "min", "minute", "minutes":
+		return "minutes"
+	case "h", "hour", "hours":
+		return "hours"
+	case "d", "day", "days":
+		return "days"
+	case "w", "week", "weeks":
+		return "weeks"
+	case "month", "months":
+		return "months"
+	case "q", "quarter", "quarters":
+		return "quarters"
+	case "y", "year", "years":
+		return "years"
+	}
+	return ""
+}
+
+// addMonths adds n months to t, the day is clamped to the end of the
+// resulting month so January 31 plus one month is the end of February.
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// dateAdd adds amount of unit to t
+func dateAdd(t time.Time, amount int, unit string) (time.Time, error) {
+	switch normalizeUnit(unit) {
+	case "nanoseconds":
+		return t.Add(time.Duration(amount)), nil
+	case "milliseconds":
+		return t.Add(time.Duration(amount) * time.Millisecond), nil
+	case "seconds":
+		return t.Add(time.Duration(amount) * time.Second), nil
+	case "minutes":
+		return t.Add(time.Duration(amount) * time.Minute), nil
+	case "hours":
+		return t.Add(time.Duration(amount) * time.Hour), nil
+	case "days":
+		return t.AddDate(0, 0, amount), nil
+	case "weeks":
+		return t.AddDate(0, 0, amount*7), nil
+	case "months":
+		return addMonths(t, amount), nil
+	case "quarters":
+		return addMonths(t, amount*3), nil
+	case "years":
+		return addMonths(t, amount*12), nil
+	}
+	return t, fmt.Errorf("unknown unit %q", unit)
+}
+
+// dateDiff returns the number of whole units from start to end, months,
+// quarters and years are counted on the calendar.
+func dateDiff(start, end time.Time, unit string) (int, error) {
+	unit = normalizeUnit(unit)
+	switch unit {
+	case "months", "quarters", "years":
+		sign := 1
+		if end.Before(start) {
+			start, end, sign = end, start, -1
+		}
+		months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+		if months > 0 && addMonths(start, months).After(end) {
+			months--
+		}
+		switch unit {
+		case "quarters":
+			return sign * (months / 3), nil
+		case "years":
+			return sign * (months / 12), nil
+		}
+		return sign * months, nil
+	case "days", "weeks":
+		// Count calendar days so a daylight saving change doesn't
+		// lose a day
+		s := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		e := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+		days := int(e.Sub(s).Hours() / 24)
+		// Drop a partial last day
+		clockS := start.Sub(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location()))
+		clockE := end.Sub(time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location()))
+		if days > 0 && clockE < clockS {
+			days--
+		} else if days < 0 && clockE > clockS {
+			days++
+		}
+		if unit == "weeks" {
+			return days / 7, nil
+		}
+		return days, nil
+	}
+	d := end.Sub(start)
+	switch unit {
+	case "nanoseconds":
+		return int(d), nil
+	case "milliseconds":
+		return int(d / time.Millisecond), nil
+	case "seconds":
+		return int(d / time.Second), nil
+	case "minutes":
+		return int(d / time.Minute), nil
+	case "hours":
+		return int(d / time.Hour), nil
+	}
+	return 0, fmt.Errorf("unknown unit %q", unit)
+}
+
+// startOf truncates t to the start of the period named by unit, weeks
+// start on Monday.
+func startOf(t time.Time, unit string) (time.Time, error) {
+	year, month, day := t.Date()
+	loc := t.Location()
+	switch normalizeUnit(unit) {
+	case "seconds":
+		return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+	case "minutes":
+		return time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, loc), nil
+	case "hours":
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, loc), nil
+	case "days":
+		return time.Date(year, month, day, 0, 0, 0, 0, loc), nil
+	case "weeks":
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, loc), nil
+	case "months":
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc), nil
+	case "quarters":
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc), nil
+	case "years":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, loc), nil
+	}
+	return t, fmt.Errorf("unknown unit %q", unit)
+}
+
+// endOf returns the last instant of the period named by unit
+func endOf(t time.Time, unit string) (time.Time, error) {
+	start, err := startOf(t, unit)
+	if err != nil {
+		return t, err
+	}
+	next, err := dateAdd(start, 1, unit)
+	if err != nil {
+		return t, err
+	}
+	return next.Add(-time.Nanosecond), nil
+}
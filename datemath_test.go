@@ -0,0 +1,103 @@
+package tmplfn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateAdd(t *testing.T) {
+	base := time.Date(2017, 1, 31, 14, 0, 0, 0, time.UTC)
+	testSet := []struct {
+		amount   int
+		unit     string
+		expected time.Time
+	}{
+		{30, "days", time.Date(2017, 3, 2, 14, 0, 0, 0, time.UTC)},
+		{1, "month", time.Date(2017, 2, 28, 14, 0, 0, 0, time.UTC)},
+		{13, "months", time.Date(2018, 2, 28, 14, 0, 0, 0, time.UTC)},
+		{-1, "year", time.Date(2016, 1, 31, 14, 0, 0, 0, time.UTC)},
+		{2, "weeks", time.Date(2017, 2, 14, 14, 0, 0, 0, time.UTC)},
+		{36, "h", time.Date(2017, 2, 2, 2, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range testSet {
+		r, err := dateAdd(base, test.amount, test.unit)
+		if err != nil {
+			t.Errorf("%d %s, unexpected error %s", test.amount, test.unit, err)
+		} else if r.Equal(test.expected) == false {
+			t.Errorf("%d %s, expected %s, got %s", test.amount, test.unit, test.expected, r)
+		}
+	}
+	if _, err := dateAdd(base, 1, "fortnight"); err == nil {
+		t.Errorf("expected an error for an unknown unit")
+	}
+}
+
+func TestDateDiff(t *testing.T) {
+	start := time.Date(2000, 2, 29, 12, 0, 0, 0, time.UTC)
+	testSet := []struct {
+		end      time.Time
+		unit     string
+		expected int
+	}{
+		{time.Date(2017, 2, 27, 12, 0, 0, 0, time.UTC), "years", 16},
+		{time.Date(2017, 2, 28, 12, 0, 0, 0, time.UTC), "years", 17},
+		{time.Date(2000, 3, 30, 11, 0, 0, 0, time.UTC), "days", 29},
+		{time.Date(2000, 3, 30, 11, 0, 0, 0, time.UTC), "months", 1},
+		{time.Date(1999, 2, 28, 12, 0, 0, 0, time.UTC), "months", -12},
+		{time.Date(2000, 3, 1, 0, 0, 0, 0, time.UTC), "hours", 12},
+	}
+	for _, test := range testSet {
+		r, err := dateDiff(start, test.end, test.unit)
+		if err != nil {
+			t.Errorf("%s, unexpected error %s", test.unit, err)
+		} else if r != test.expected {
+			t.Errorf("%s to %s in %s, expected %d, got %d", start, test.end, test.unit, test.expected, r)
+		}
+	}
+
+	dateDiffFn := Time["date_diff"].(func(interface{}, interface{}, string) (int, error))
+	if r, err := dateDiffFn("2017-01-01", "2017-03-16", "weeks"); err != nil || r != 10 {
+		t.Errorf("expected 10 weeks, got %d, %v", r, err)
+	}
+	if r, err := dateDiffFn("2017-01-01", "2017-03-16", "fortnights"); err == nil {
+		t.Errorf("expected an error for an unknown unit, got %d", r)
+	}
+	if r, err := dateDiffFn("2017-01-01", "someday", "days"); err == nil {
+		t.Errorf("expected an error for an unparsable date, got %d", r)
+	}
+}
+
+func TestDateComparisons(t *testing.T) {
+	dateBefore := Time["date_before"].(func(interface{}, interface{}) bool)
+	dateBetween := Time["date_between"].(func(interface{}, interface{}, interface{}) bool)
+	if dateBefore("2017-03-16", "2017-03-16T00:00:01Z") == false {
+		t.Errorf("expected 2017-03-16 to be before 2017-03-16T00:00:01Z")
+	}
+	if dateBefore("2017~", "2018") {
+		t.Errorf("expected false for an unparsable date")
+	}
+	if dateBetween("2017-06", "2017", "2017-12-31") == false {
+		t.Errorf("expected 2017-06 to be between 2017 and 2017-12-31")
+	}
+
+	endOfFn := Time["end_of"].(func(string, interface{}) (time.Time, error))
+	rfc3339 := Time["rfc3339"].(func(interface{}) string)
+	if dt, err := endOfFn("quarter", "2017-05-16"); err != nil || rfc3339(dt) != "2017-06-30T23:59:59Z" {
+		t.Errorf("expected 2017-06-30T23:59:59Z, got %q, %v", rfc3339(dt), err)
+	}
+	startOfFn := Time["start_of"].(func(string, interface{}) (time.Time, error))
+	if dt, err := startOfFn("week", "2017-03-16T14:22:05Z"); err != nil || rfc3339(dt) != "2017-03-13T00:00:00Z" {
+		t.Errorf("expected 2017-03-13T00:00:00Z, got %q, %v", rfc3339(dt), err)
+	}
+	if _, err := startOfFn("fortnight", "2017-03-16"); err == nil {
+		t.Errorf("expected an error for an unknown unit")
+	}
+
+	f, err := ParseFilter(`(date_after (date_add 30 "days" .pub_date) "2017-04-01")`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if ok, err := f.Apply(map[string]interface{}{"pub_date": "2017-03-16"}); err != nil || ok == false {
+		t.Errorf("expected filter to match, %t, %v", ok, err)
+	}
+}
@@ -0,0 +1,174 @@
+package tmplfn
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// namedLayouts are complete layouts tried before the date and clock
+	// parts of a string are taken apart. These cover the formats the
+	// Time functions themselves produce (e.g. rfc1123) so their output
+	// can be fed back in.
+	namedLayouts = []string{
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC850,
+		time.RFC822Z,
+		time.RFC822,
+		time.ANSIC,
+		time.UnixDate,
+		time.RubyDate,
+	}
+
+	// clockLayouts are the time of day layouts recognized after the
+	// zone has been removed. Fractional seconds are accepted by
+	// time.Parse immediately after the seconds field.
+	clockLayouts = []string{
+		"15:04:05",
+		"15:04",
+		"150405",
+		"1504",
+		"15",
+	}
+
+	reDigits    = regexp.MustCompile(`^[0-9]+$`)
+	reUnixTime  = regexp.MustCompile(`^(@-?[0-9]+|-?[0-9]{10,})(\.[0-9]+)?$`)
+	reDatePart  = regexp.MustCompile(`^[0-9]{1,4}(-[0-9]{1,2}(-[0-9]{1,2})?)?$`)
+	reZoneTail  = regexp.MustCompile(`(Z|z|[+-][0-9]{2}(:?[0-9]{2})?)$`)
+	reClockPart = regexp.MustCompile(`^[0-9:]+([.,][0-9]+)?$`)
+)
+
+// ParseDateTime parses a date or datetime string the same way the
+// Time functions do. It recognizes, in order,
+//
+//	Unix timestamps (e.g. @86400, @1489674125.5 or, with ten or more
+//	digits, 1489674125)
+//	RFC 1123, RFC 850, RFC 822, ANSI C and Unix date layouts
+//	YYYY, YYYY-MM, YYYY-MM-DD, YYYYMM and YYYYMMDD dates
+//	any of the above dates followed by "T" or a space and a time of
+//	day (hh, hh:mm, hh:mm:ss, hhmmss) with optional fractional
+//	seconds and an optional zone (Z, +hh, +hhmm or +hh:mm)
+//
+// Dates are normalized before parsing so 2016-4-3 is read as 2016-04-03.
+// Values without a zone are returned in UTC.
+func ParseDateTime(s string) (time.Time, error) {
+	return parseDateTimeIn(s, time.UTC)
+}
+
+// parseDateTimeIn implements ParseDateTime, values without a zone are
+// placed in loc.
+func parseDateTimeIn(s string, loc *time.Location) (time.Time, error) {
+	src := strings.TrimSpace(s)
+	if src == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	// Unix timestamps start with "@" or have ten or more digits, shorter
+	// strings of digits are dates (YYYY, YYYYMM or YYYYMMDD)
+	if reUnixTime.MatchString(src) {
+		return parseUnixTime(strings.TrimPrefix(src, "@"))
+	}
+
+	for _, layout := range namedLayouts {
+		if dt, err := time.ParseInLocation(layout, src, loc); err == nil {
+			return dt, nil
+		}
+	}
+
+	// Split into the date part and the clock part
+	datePart, clockPart := src, ""
+	if i := strings.IndexAny(src, "Tt "); i > 0 {
+		datePart, clockPart = src[:i], strings.TrimSpace(src[i+1:])
+	}
+	day, err := parseDateOnly(datePart, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q, %s", s, err)
+	}
+	if clockPart == "" {
+		return day, nil
+	}
+
+	// Split the zone from the clock part
+	zone := reZoneTail.FindString(clockPart)
+	clock := strings.TrimSpace(strings.TrimSuffix(clockPart, zone))
+	if reClockPart.MatchString(clock) == false {
+		return time.Time{}, fmt.Errorf("%q, can't parse time of day %q", s, clockPart)
+	}
+	var tod time.Time
+	for _, layout := range clockLayouts {
+		if tod, err = time.Parse(layout, strings.Replace(clock, ",", ".", 1)); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q, can't parse time of day %q", s, clock)
+	}
+	zoneLoc, err := parseZoneOffset(zone, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q, %s", s, err)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), tod.Hour(), tod.Minute(), tod.Second(), tod.Nanosecond(), zoneLoc), nil
+}
+
+// parseDateOnly parses YYYY, YYYY-MM, YYYY-MM-DD, YYYYMM or YYYYMMDD
+// in loc. A month or day of 00 is a placeholder read as 01, a month or
+// day past the end of the year or month is an error.
+func parseDateOnly(s string, loc *time.Location) (time.Time, error) {
+	if reDigits.MatchString(s) {
+		switch len(s) {
+		case 6:
+			s = s[0:4] + "-" + s[4:6]
+		case 8:
+			s = s[0:4] + "-" + s[4:6] + "-" + s[6:8]
+		}
+	}
+	if reDatePart.MatchString(s) == false {
+		return time.Time{}, fmt.Errorf("can't parse date %q", s)
+	}
+	return time.ParseInLocation("2006-01-02", normalizeDate(s), loc)
+}
+
+// parseUnixTime parses seconds since the Unix epoch with an optional
+// fractional part, the result is in UTC.
+func parseUnixTime(s string) (time.Time, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can't parse timestamp %q, %s", s, err)
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), nil
+}
+
+// parseZoneOffset turns Z, +hh, +hhmm or +hh:mm into a location, an
+// empty zone returns loc.
+func parseZoneOffset(zone string, loc *time.Location) (*time.Location, error) {
+	switch zone {
+	case "":
+		return loc, nil
+	case "Z", "z":
+		return time.UTC, nil
+	}
+	sign := 1
+	if zone[0] == '-' {
+		sign = -1
+	}
+	digits := strings.Replace(zone[1:], ":", "", 1)
+	hours, _ := strconv.Atoi(digits[0:2])
+	minutes := 0
+	if len(digits) == 4 {
+		minutes, _ = strconv.Atoi(digits[2:4])
+	}
+	if hours > 23 || minutes > 59 {
+		return nil, fmt.Errorf("invalid zone offset %q", zone)
+	}
+	offset := sign * (hours*3600 + minutes*60)
+	if offset == 0 {
+		return time.UTC, nil
+	}
+	return time.FixedZone("", offset), nil
+}
@@ -0,0 +1,128 @@
+package tmplfn
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+	pst := time.FixedZone("", -7*3600)
+	testSet := map[string]time.Time{
+		"2017":                          time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2017-03":                       time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2016-4-3":                      time.Date(2016, 4, 3, 0, 0, 0, 0, time.UTC),
+		"20170316":                      time.Date(2017, 3, 16, 0, 0, 0, 0, time.UTC),
+		"201703":                        time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2017-08-00":                    time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC),
+		"2017-00-00":                    time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		"1992-00":                       time.Date(1992, 1, 1, 0, 0, 0, 0, time.UTC),
+		"20170300":                      time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2017-03-16 14:22":              time.Date(2017, 3, 16, 14, 22, 0, 0, time.UTC),
+		"2017-03-16T14:22:05Z":          time.Date(2017, 3, 16, 14, 22, 5, 0, time.UTC),
+		"2017-03-16T14:22:05-07:00":     time.Date(2017, 3, 16, 14, 22, 5, 0, pst),
+		"2017-03-16T14:22:05-0700":      time.Date(2017, 3, 16, 14, 22, 5, 0, pst),
+		"2017-03-16T14:22:05.25+00:00":  time.Date(2017, 3, 16, 14, 22, 5, 250000000, time.UTC),
+		"2017-03-16 14:22:05,5 -07":     time.Date(2017, 3, 16, 14, 22, 5, 500000000, pst),
+		"1489674125":                    time.Date(2017, 3, 16, 14, 22, 5, 0, time.UTC),
+		"@1489674125":                   time.Date(2017, 3, 16, 14, 22, 5, 0, time.UTC),
+		"@86400":                        time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC),
+		"Thu, 16 Mar 2017 14:22:05 UTC": time.Date(2017, 3, 16, 14, 22, 5, 0, time.UTC),
+	}
+	for s, expected := range testSet {
+		dt, err := ParseDateTime(s)
+		if err != nil {
+			t.Errorf("%q, unexpected error %s", s, err)
+			continue
+		}
+		if dt.Equal(expected) == false {
+			t.Errorf("%q, expected %s, got %s", s, expected, dt)
+		}
+	}
+
+	for _, s := range []string{"", "not a date", "2017-13-01", "2017-03-16T25:00", "2017-03-16Tnoon",
+		"2017-02-29", "2017-04-31", "2017-03-32", "201713", "20173", "2017031", "148967412", "2017.5"} {
+		if dt, err := ParseDateTime(s); err == nil {
+			t.Errorf("%q, expected an error, got %s", s, dt)
+		}
+	}
+}
+
+func TestTimeFuncsKeepTimeOfDay(t *testing.T) {
+	timefmt := Time["timefmt"].(func(interface{}, string) string)
+	s := "2017-03-16T14:22:05-07:00"
+	expected := "2017-03-16 14:22:05 -0700"
+	if r := timefmt(s, "2006-01-02 15:04:05 -0700"); r != expected {
+		t.Errorf("expected %q, got %q", expected, r)
+	}
+	rfc3339 := Time["rfc3339"].(func(interface{}) string)
+	expected = "2017-03-16T14:22:00Z"
+	if r := rfc3339("2017-03-16 14:22"); r != expected {
+		t.Errorf("expected %q, got %q", expected, r)
+	}
+	year := Time["year"].(func(interface{}) string)
+	if r := year("2017~"); r != "" {
+		t.Errorf("expected empty string for unparsable date, got %q", r)
+	}
+}
+
+func TestTimeZones(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("can't load zone, %s", err)
+	}
+	fm := TimeFuncMap(&TimeOptions{Location: loc})
+	timefmt := fm["timefmt"].(func(interface{}, string) string)
+	expected := "2017-03-16 07:22 PDT"
+	if r := timefmt("2017-03-16T14:22:00Z", "2006-01-02 15:04 MST"); r != expected {
+		t.Errorf("expected %q, got %q", expected, r)
+	}
+	// Dates without a zone are read in the default zone
+	zoneOffset := fm["zone_offset"].(func(interface{}) string)
+	if r := zoneOffset("2017-01-16 14:22"); r != "-08:00" {
+		t.Errorf("expected -08:00, got %q", r)
+	}
+
+	inZone := Time["in_zone"].(func(string, interface{}) (time.Time, error))
+	zoneAbbr := Time["zone_abbr"].(func(interface{}) string)
+	zoneName := Time["zone_name"].(func(interface{}) string)
+	dt, err := inZone("Asia/Tokyo", "2017-03-16T14:22:00Z")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if r := zoneAbbr(dt); r != "JST" {
+		t.Errorf("expected JST, got %q", r)
+	}
+	if r := zoneName(dt); r != "Asia/Tokyo" {
+		t.Errorf("expected Asia/Tokyo, got %q", r)
+	}
+	// in_zone isn't undone by the FuncMap's Location
+	src := `{{ timefmt (in_zone "Asia/Tokyo" .d) "15:04 MST" }} {{ zone_name (in_zone "Asia/Tokyo" .d) }} {{ timefmt .d "15:04 MST" }}`
+	tmpl, err := assembleString(fm, src)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, map[string]interface{}{"d": "2017-03-16T14:22:00Z"}); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "23:22 JST Asia/Tokyo 07:22 PDT"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+	if r, err := inZone("Nowhere/Special", "2017-03-16"); err == nil {
+		t.Errorf("expected an error for unknown zone, got %v", r)
+	}
+	tmpl, _ = assembleString(Time, `{{ timefmt (in_zone "Nowhere/Special" .d) "15:04" }}`)
+	if err := tmpl.Execute(bytes.NewBuffer([]byte{}), map[string]interface{}{"d": "2017-03-16"}); err == nil {
+		t.Errorf("expected Execute to fail for an unknown zone")
+	}
+
+	// Unix timestamps decoded from JSON without UseNumber are float64
+	year := Time["year"].(func(interface{}) string)
+	for _, v := range []interface{}{float64(1489674125), uint64(1489674125), int32(1489674125), json.Number("1489674125"), 2017.0} {
+		if r := year(v); r != "2017" {
+			t.Errorf("%T %v, expected 2017, got %q", v, v, r)
+		}
+	}
+}
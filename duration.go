@@ -0,0 +1,186 @@
+package tmplfn
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/numbers"
+)
+
+var (
+	reISODuration = regexp.MustCompile(`^([+-])?P(?:([0-9.,]+)Y)?(?:([0-9.,]+)M)?(?:([0-9.,]+)W)?(?:([0-9.,]+)D)?(?:T(?:([0-9.,]+)H)?(?:([0-9.,]+)M)?(?:([0-9.,]+)S)?)?$`)
+
+	// isoDurationUnits are the lengths of the ISO 8601 duration
+	// fields, years and months have no fixed length so they are
+	// counted as 365 and 30 days
+	isoDurationUnits = []time.Duration{
+		365 * 24 * time.Hour,
+		30 * 24 * time.Hour,
+		7 * 24 * time.Hour,
+		24 * time.Hour,
+		time.Hour,
+		time.Minute,
+		time.Second,
+	}
+
+	// durationUnits are the units used to render durations, largest first
+	durationUnits = []struct {
+		name string
+		size time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+	}
+)
+
+// ParseDuration converts v to a time.Duration. Numbers (any kind the
+// numbers package accepts) and numeric strings are read as seconds,
+// strings starting with P are read as ISO 8601 durations (e.g. PT36H,
+// P1DT12H, P2W) and other strings as Go durations (e.g. 90m, 1h30m).
+// ISO 8601 years and months are counted as 365 and 30 days. Durations
+// longer than about 292 years are an error.
+func ParseDuration(v interface{}) (time.Duration, error) {
+	switch x := v.(type) {
+	case time.Duration:
+		return x, nil
+	case string:
+		s := strings.TrimSpace(x)
+		if s == "" {
+			return 0, fmt.Errorf("empty duration")
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return secondsToDuration(f)
+		}
+		if strings.HasPrefix(strings.TrimLeft(s, "+-"), "P") {
+			return parseISODuration(s)
+		}
+		return time.ParseDuration(s)
+	}
+	if numbers.IsNumber(v) == false {
+		return 0, fmt.Errorf("can't convert %T to a duration", v)
+	}
+	// Integer seconds stay exact, an overflow is an error
+	ns, err := numbers.Policy{Strict: true}.Multiply(v, int64(time.Second))
+	if err != nil {
+		return 0, fmt.Errorf("duration %v is too long", v)
+	}
+	switch x := ns.(type) {
+	case int64:
+		return time.Duration(x), nil
+	case float64:
+		return nanosecondsToDuration(v, x)
+	case float32:
+		return nanosecondsToDuration(v, float64(x))
+	}
+	return 0, fmt.Errorf("duration %v is too long", v)
+}
+
+// secondsToDuration converts f seconds to a time.Duration
+func secondsToDuration(f float64) (time.Duration, error) {
+	return nanosecondsToDuration(f, f*float64(time.Second))
+}
+
+// nanosecondsToDuration rounds f nanoseconds to a time.Duration or
+// returns an error naming v if it is out of range
+func nanosecondsToDuration(v interface{}, f float64) (time.Duration, error) {
+	f = math.Round(f)
+	// -2^63 is the shortest duration, 2^63 is one more than the longest
+	if math.IsNaN(f) || f < -(1<<63) || f >= 1<<63 {
+		return 0, fmt.Errorf("duration %v is too long", v)
+	}
+	return time.Duration(f), nil
+}
+
+// parseISODuration parses an ISO 8601 duration, e.g. P1DT12H
+func parseISODuration(s string) (time.Duration, error) {
+	m := reISODuration.FindStringSubmatch(s)
+	if m == nil || strings.HasSuffix(s, "P") || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("can't parse ISO 8601 duration %q", s)
+	}
+	var total float64
+	for i, field := range m[2:] {
+		if field == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.Replace(field, ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("can't parse ISO 8601 duration %q, %s", s, err)
+		}
+		total += f * float64(isoDurationUnits[i])
+	}
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("ISO 8601 duration %q is too long", s)
+	}
+	if m[1] == "-" {
+		total = -total
+	}
+	return time.Duration(math.Round(total)), nil
+}
+
+// pluralUnit returns "1 day" or "n days"
+func pluralUnit(n int64, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// HumanizeDuration renders d in days, hours, minutes and seconds,
+// e.g. 36h is "1 day 12 hours". Durations under a second are written
+// in milliseconds.
+func HumanizeDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d < time.Second {
+		return sign + pluralUnit(int64(d/time.Millisecond), "millisecond")
+	}
+	var parts []string
+	for _, unit := range durationUnits {
+		if n := int64(d / unit.size); n > 0 {
+			parts = append(parts, pluralUnit(n, unit.name))
+			d -= time.Duration(n) * unit.size
+		}
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// ClockDuration renders d as hours, minutes and seconds, e.g. 36h is
+// "36:00:00"
+func ClockDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	d = d.Round(time.Second)
+	hours := int64(d / time.Hour)
+	minutes := int64(d % time.Hour / time.Minute)
+	seconds := int64(d % time.Minute / time.Second)
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, minutes, seconds)
+}
+
+// RoundDuration renders d rounded to its largest unit, e.g. 36h is
+// "2 days" and 90m is "2 hours"
+func RoundDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d < time.Second {
+		return sign + pluralUnit(int64(d.Round(time.Millisecond)/time.Millisecond), "millisecond")
+	}
+	for _, unit := range durationUnits {
+		if d >= unit.size {
+			return sign + pluralUnit(int64(math.Round(float64(d)/float64(unit.size))), unit.name)
+		}
+	}
+	return sign + pluralUnit(0, "second")
+}
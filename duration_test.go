@@ -0,0 +1,74 @@
+package tmplfn
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+// seconds is a named numeric type like those found in application structs
+type seconds int32
+
+func TestParseDuration(t *testing.T) {
+	testSet := map[interface{}]time.Duration{
+		"PT36H":               36 * time.Hour,
+		"P1DT12H":             36 * time.Hour,
+		"P2W":                 14 * 24 * time.Hour,
+		"PT1.5M":              90 * time.Second,
+		"-PT90M":              -90 * time.Minute,
+		"90m":                 90 * time.Minute,
+		"1h30m":               90 * time.Minute,
+		"5400":                90 * time.Minute,
+		5400:                  90 * time.Minute,
+		json.Number("129600"): 36 * time.Hour,
+		2.5:                   2500 * time.Millisecond,
+		int32(60):             time.Minute,
+		uint(60):              time.Minute,
+		uint64(60):            time.Minute,
+		float32(0.5):          500 * time.Millisecond,
+		int64(-60):            -time.Minute,
+		seconds(90):           90 * time.Second,
+	}
+	for v, expected := range testSet {
+		d, err := ParseDuration(v)
+		if err != nil {
+			t.Errorf("%v, unexpected error %s", v, err)
+		} else if d != expected {
+			t.Errorf("%v, expected %s, got %s", v, expected, d)
+		}
+	}
+	for _, v := range []interface{}{"", "P", "PT", "P1H", "ninety minutes", true,
+		1e10, -1e10, "1e10", math.NaN(), math.Inf(1), int64(1e10), uint64(math.MaxUint64), json.Number("10000000000")} {
+		if d, err := ParseDuration(v); err == nil {
+			t.Errorf("%v, expected an error, got %s", v, d)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	d := 36 * time.Hour
+	if r := HumanizeDuration(d); r != "1 day 12 hours" {
+		t.Errorf("expected %q, got %q", "1 day 12 hours", r)
+	}
+	if r := HumanizeDuration(time.Hour + 1*time.Second); r != "1 hour 1 second" {
+		t.Errorf("expected %q, got %q", "1 hour 1 second", r)
+	}
+	if r := ClockDuration(d); r != "36:00:00" {
+		t.Errorf("expected %q, got %q", "36:00:00", r)
+	}
+	if r := ClockDuration(-(90*time.Minute + 5*time.Second)); r != "-01:30:05" {
+		t.Errorf("expected %q, got %q", "-01:30:05", r)
+	}
+	if r := RoundDuration(d); r != "2 days" {
+		t.Errorf("expected %q, got %q", "2 days", r)
+	}
+	if r := RoundDuration(89 * time.Minute); r != "1 hour" {
+		t.Errorf("expected %q, got %q", "1 hour", r)
+	}
+
+	durationHuman := Time["duration_human"].(func(interface{}) string)
+	if r := durationHuman("PT36H"); r != "1 day 12 hours" {
+		t.Errorf("expected %q, got %q", "1 day 12 hours", r)
+	}
+}
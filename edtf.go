@@ -0,0 +1,62 @@
+package tmplfn
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/edtf"
+)
+
+var (
+	// EDTF provides functions for working with Extended Date/Time Format
+	// strings (e.g. 2017~, 201X, 1984?/2004-06) found in library metadata
+	EDTF = template.FuncMap{
+		// edtf_valid returns true if s is a valid EDTF string
+		"edtf_valid": edtf.Valid,
+		// edtf_level returns the EDTF level (0, 1 or 2) of s or -1 if s is not valid
+		"edtf_level": func(s string) int {
+			if e, err := edtf.Parse(s); err == nil {
+				return e.Level
+			}
+			return -1
+		},
+		// edtf_human renders s in English (e.g. "circa 2017"), invalid strings are returned as is
+		"edtf_human": func(s string) string {
+			if e, err := edtf.Parse(s); err == nil {
+				return e.Human()
+			}
+			return s
+		},
+		// edtf_earliest returns the earliest date of s as YYYY-MM-DD or an
+		// empty string if s is invalid or open at the start
+		"edtf_earliest": func(s string) string {
+			if e, err := edtf.Parse(s); err == nil {
+				if t, ok := e.Earliest(); ok {
+					return formatEDTFDay(t)
+				}
+			}
+			return ""
+		},
+		// edtf_latest returns the latest date of s as YYYY-MM-DD or an
+		// empty string if s is invalid or open at the end
+		"edtf_latest": func(s string) string {
+			if e, err := edtf.Parse(s); err == nil {
+				if t, ok := e.Latest(); ok {
+					return formatEDTFDay(t)
+				}
+			}
+			return ""
+		},
+	}
+)
+
+// formatEDTFDay formats t as YYYY-MM-DD, unlike time.Format years
+// outside 0 to 9999 are written in full.
+func formatEDTFDay(t time.Time) string {
+	if t.Year() < 0 {
+		return fmt.Sprintf("-%04d-%02d-%02d", -t.Year(), t.Month(), t.Day())
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", t.Year(), t.Month(), t.Day())
+}
@@ -0,0 +1,684 @@
+package edtf
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Qualifier marks a date component as uncertain (?), approximate (~)
+// or both (%)
+type Qualifier int
+
+const (
+	// Uncertain is written as "?"
+	Uncertain Qualifier = 1 << iota
+	// Approximate is written as "~"
+	Approximate
+)
+
+// Precision is the finest component a date specifies
+type Precision int
+
+const (
+	// YearPrecision dates specify only a year, e.g. 2004
+	YearPrecision Precision = iota
+	// SeasonPrecision dates specify a year and a season, e.g. 2004-21
+	SeasonPrecision
+	// MonthPrecision dates specify a year and month, e.g. 2004-06
+	MonthPrecision
+	// DayPrecision dates specify a year, month and day, e.g. 2004-06-11
+	DayPrecision
+	// TimePrecision dates include a time of day, e.g. 2004-06-11T10:20:30Z
+	TimePrecision
+)
+
+// Date is a single EDTF date. Components written with unspecified
+// digits (X) hold the value with each X read as zero.
+type Date struct {
+	Year   int
+	Month  int
+	Day    int
+	Season int
+	// Significant is the number of significant digits of Year, zero
+	// if not given (e.g. 1950S2 has 2)
+	Significant int
+	Precision   Precision
+	// YearQualifier, MonthQualifier and DayQualifier hold the
+	// qualification of each component
+	YearQualifier  Qualifier
+	MonthQualifier Qualifier
+	DayQualifier   Qualifier
+	// Time holds the full datetime when Precision is TimePrecision
+	Time time.Time
+
+	hasTime  bool
+	long     bool
+	level    int
+	yearRaw  string
+	monthRaw string
+	dayRaw   string
+}
+
+var (
+	monthNames = []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+
+	// seasons maps season codes to a name, the first month and the
+	// number of months covered
+	seasons = map[int]struct {
+		name   string
+		month  int
+		months int
+	}{
+		21: {"spring", 3, 3},
+		22: {"summer", 6, 3},
+		23: {"autumn", 9, 3},
+		24: {"winter", 12, 3},
+		25: {"spring (Northern Hemisphere)", 3, 3},
+		26: {"summer (Northern Hemisphere)", 6, 3},
+		27: {"autumn (Northern Hemisphere)", 9, 3},
+		28: {"winter (Northern Hemisphere)", 12, 3},
+		29: {"spring (Southern Hemisphere)", 9, 3},
+		30: {"summer (Southern Hemisphere)", 12, 3},
+		31: {"autumn (Southern Hemisphere)", 3, 3},
+		32: {"winter (Southern Hemisphere)", 6, 3},
+		33: {"first quarter of", 1, 3},
+		34: {"second quarter of", 4, 3},
+		35: {"third quarter of", 7, 3},
+		36: {"fourth quarter of", 10, 3},
+		37: {"first quadrimester of", 1, 4},
+		38: {"second quadrimester of", 5, 4},
+		39: {"third quadrimester of", 9, 4},
+		40: {"first semester of", 1, 6},
+		41: {"second semester of", 7, 6},
+	}
+
+	reTime = regexp.MustCompile(`^([0-9]{2}):([0-9]{2}):([0-9]{2})(Z|[+-][0-9]{2}(:[0-9]{2})?)?$`)
+)
+
+// scanner walks an EDTF date string
+type scanner struct {
+	src string
+	pos int
+}
+
+func (s *scanner) done() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *scanner) peek() byte {
+	if s.done() {
+		return 0
+	}
+	return s.src[s.pos]
+}
+
+// qualifier reads an optional ?, ~ or %
+func (s *scanner) qualifier() Qualifier {
+	switch s.peek() {
+	case '?':
+		s.pos++
+		return Uncertain
+	case '~':
+		s.pos++
+		return Approximate
+	case '%':
+		s.pos++
+		return Uncertain | Approximate
+	}
+	return 0
+}
+
+// run reads bytes while accept is true
+func (s *scanner) run(accept func(byte) bool) string {
+	start := s.pos
+	for s.done() == false && accept(s.src[s.pos]) {
+		s.pos++
+	}
+	return s.src[start:s.pos]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isDigitOrX(c byte) bool {
+	return isDigit(c) || c == 'X'
+}
+
+// parseDate parses a single EDTF date
+func parseDate(src string) (*Date, error) {
+	var (
+		d                      = &Date{Precision: YearPrecision}
+		s                      = &scanner{src: src}
+		left, right            [3]Qualifier
+		componentQualification bool
+	)
+
+	// Year
+	left[0] = s.qualifier()
+	if err := d.parseYear(s); err != nil {
+		return nil, err
+	}
+	right[0] = s.qualifier()
+	components := 1
+
+	// Month or season
+	if s.peek() == '-' {
+		s.pos++
+		left[1] = s.qualifier()
+		d.monthRaw = s.run(isDigitOrX)
+		if len(d.monthRaw) != 2 {
+			return nil, fmt.Errorf("expected a two digit month")
+		}
+		right[1] = s.qualifier()
+		components++
+	}
+
+	// Day
+	if s.peek() == '-' {
+		s.pos++
+		left[2] = s.qualifier()
+		d.dayRaw = s.run(isDigitOrX)
+		if len(d.dayRaw) != 2 {
+			return nil, fmt.Errorf("expected a two digit day")
+		}
+		right[2] = s.qualifier()
+		components++
+	}
+
+	// Time
+	if s.peek() == 'T' {
+		if components != 3 {
+			return nil, fmt.Errorf("a time requires a complete date")
+		}
+		s.pos++
+		if err := d.parseTime(s.src[s.pos:]); err != nil {
+			return nil, err
+		}
+		s.pos = len(s.src)
+	}
+	if s.done() == false {
+		return nil, fmt.Errorf("unexpected %q", s.src[s.pos:])
+	}
+
+	// A qualifier to the right of a component applies to it and every
+	// component to its left, one to the left applies to it alone.
+	qualifiers := [3]Qualifier{}
+	for i := 0; i < components; i++ {
+		qualifiers[i] |= left[i]
+		for j := 0; j <= i; j++ {
+			qualifiers[j] |= right[i]
+		}
+		if left[i] != 0 || (right[i] != 0 && i != components-1) {
+			componentQualification = true
+		}
+	}
+	d.YearQualifier, d.MonthQualifier, d.DayQualifier = qualifiers[0], qualifiers[1], qualifiers[2]
+	if d.hasTime && (qualifiers != [3]Qualifier{}) {
+		return nil, fmt.Errorf("a datetime can't be qualified")
+	}
+
+	if err := d.parseMonthDay(); err != nil {
+		return nil, err
+	}
+
+	// Work out the level
+	switch {
+	case componentQualification:
+		d.level = 2
+	case d.YearQualifier != 0:
+		d.level = maxInt(d.level, 1)
+	}
+	return d, nil
+}
+
+// maxLongYear is the largest number of years before or after year
+// zero a long year (e.g. Y17E7) can have
+const maxLongYear = 100000000000
+
+// parseYear reads the year and an optional significant digits suffix
+func (d *Date) parseYear(s *scanner) error {
+	if s.peek() == 'Y' {
+		// Long years, Y170000002, Y-17E7
+		start := s.pos
+		s.pos++
+		sign := ""
+		if s.peek() == '-' {
+			sign = "-"
+			s.pos++
+		}
+		digits := s.run(isDigit)
+		if digits == "" {
+			return fmt.Errorf("expected digits after Y")
+		}
+		exponent := 0
+		if s.peek() == 'E' {
+			s.pos++
+			e := s.run(isDigit)
+			if e == "" {
+				return fmt.Errorf("expected an exponent after E")
+			}
+			exponent, _ = strconv.Atoi(e)
+			d.level = 2
+		} else if len(digits) <= 4 {
+			return fmt.Errorf("Y is only used for years with more than four digits")
+		} else {
+			d.level = 1
+		}
+		// Earliest and Latest can't represent years much beyond maxLongYear
+		y, err := strconv.ParseInt(sign+digits, 10, 64)
+		if err != nil || exponent > 11 {
+			return fmt.Errorf("year %s out of range", s.src[start:s.pos])
+		}
+		scale := int64(math.Pow10(exponent))
+		if y > maxLongYear/scale || y < -maxLongYear/scale || int64(int(y*scale)) != y*scale {
+			return fmt.Errorf("year %s out of range", s.src[start:s.pos])
+		}
+		year := int(y * scale)
+		d.Year = year
+		d.yearRaw = strconv.Itoa(year)
+		d.long = true
+	} else {
+		sign := ""
+		if s.peek() == '-' {
+			sign = "-"
+			s.pos++
+			d.level = 1
+		}
+		digits := s.run(isDigitOrX)
+		if len(digits) != 4 {
+			return fmt.Errorf("expected a four digit year")
+		}
+		d.yearRaw = sign + digits
+		d.Year, _ = strconv.Atoi(sign + strings.Replace(digits, "X", "0", -1))
+		if sign == "-" && d.Year == 0 && strings.Contains(digits, "X") == false {
+			return fmt.Errorf("year zero can't be negative")
+		}
+		if i := strings.Index(digits, "X"); i >= 0 {
+			if i >= 2 && strings.TrimRight(digits, "X") == digits[:i] {
+				d.level = maxInt(d.level, 1)
+			} else {
+				d.level = 2
+			}
+		}
+	}
+	if s.peek() == 'S' {
+		s.pos++
+		sig := s.run(isDigit)
+		n, err := strconv.Atoi(sig)
+		if err != nil || n < 1 || n > len(strings.TrimPrefix(d.yearRaw, "-")) {
+			return fmt.Errorf("invalid significant digits %q", sig)
+		}
+		if strings.Contains(d.yearRaw, "X") {
+			return fmt.Errorf("significant digits can't be combined with unspecified digits")
+		}
+		d.Significant = n
+		d.level = 2
+	}
+	return nil
+}
+
+// parseMonthDay validates the month (or season) and day components
+func (d *Date) parseMonthDay() error {
+	yearX := strings.Contains(d.yearRaw, "X")
+	if d.monthRaw == "" {
+		return nil
+	}
+	if d.long || d.Significant > 0 {
+		return fmt.Errorf("long years can't have a month or day")
+	}
+	d.Precision = MonthPrecision
+	d.Month, _ = strconv.Atoi(strings.Replace(d.monthRaw, "X", "0", -1))
+	switch {
+	case d.monthRaw == "XX":
+		// Level 1 allows a fully unspecified month on a known year
+		if yearX {
+			d.level = 2
+		} else {
+			d.level = maxInt(d.level, 1)
+		}
+	case strings.Contains(d.monthRaw, "X"):
+		if d.monthRaw[1] == 'X' && d.monthRaw[0] > '1' {
+			return fmt.Errorf("invalid month %q", d.monthRaw)
+		}
+		d.level = 2
+	case d.Month >= 1 && d.Month <= 12:
+		if yearX {
+			d.level = 2
+		}
+	case d.Month >= 21 && d.Month <= 41:
+		if d.dayRaw != "" {
+			return fmt.Errorf("a season can't have a day")
+		}
+		d.Season, d.Month = d.Month, 0
+		d.Precision = SeasonPrecision
+		if d.Season > 24 {
+			d.level = 2
+		} else {
+			d.level = maxInt(d.level, 1)
+		}
+		if yearX {
+			d.level = 2
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid month %q", d.monthRaw)
+	}
+
+	if d.dayRaw == "" {
+		return nil
+	}
+	d.Precision = DayPrecision
+	if d.hasTime {
+		d.Precision = TimePrecision
+	}
+	d.Day, _ = strconv.Atoi(strings.Replace(d.dayRaw, "X", "0", -1))
+	switch {
+	case d.dayRaw == "XX":
+		if yearX || strings.Contains(d.monthRaw, "X") && d.monthRaw != "XX" {
+			d.level = 2
+		} else {
+			d.level = maxInt(d.level, 1)
+		}
+	case strings.Contains(d.dayRaw, "X"):
+		if d.dayRaw[0] > '3' && d.dayRaw[0] != 'X' {
+			return fmt.Errorf("invalid day %q", d.dayRaw)
+		}
+		d.level = 2
+	default:
+		if d.monthRaw == "XX" || yearX || strings.Contains(d.monthRaw, "X") {
+			d.level = 2
+		}
+		if d.Day < 1 || d.Day > d.maxDay() {
+			return fmt.Errorf("invalid day %q", d.dayRaw)
+		}
+	}
+	return nil
+}
+
+// parseTime parses the part of a datetime after the T
+func (d *Date) parseTime(src string) error {
+	if strings.Contains(d.yearRaw+d.monthRaw+d.dayRaw, "X") {
+		return fmt.Errorf("a datetime can't have unspecified digits")
+	}
+	m := reTime.FindStringSubmatch(src)
+	if m == nil {
+		return fmt.Errorf("can't parse time %q", src)
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	second, _ := strconv.Atoi(m[3])
+	if hour > 23 || minute > 59 || second > 59 {
+		return fmt.Errorf("invalid time %q", src)
+	}
+	loc := time.UTC
+	if zone := m[4]; zone != "" && zone != "Z" {
+		offsetHours, _ := strconv.Atoi(zone[1:3])
+		offsetMinutes := 0
+		if len(zone) == 6 {
+			offsetMinutes, _ = strconv.Atoi(zone[4:6])
+		}
+		offset := offsetHours*3600 + offsetMinutes*60
+		if zone[0] == '-' {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+	}
+	month, _ := strconv.Atoi(d.monthRaw)
+	day, _ := strconv.Atoi(d.dayRaw)
+	d.Time = time.Date(d.Year, time.Month(month), day, hour, minute, second, 0, loc)
+	d.hasTime = true
+	return nil
+}
+
+// maxDay returns the last possible day of the month, when the year
+// or month are unspecified the longest possibility is used.
+func (d *Date) maxDay() int {
+	_, monthHi := d.monthRange()
+	if strings.Contains(d.yearRaw, "X") {
+		if monthHi == 2 {
+			return 29
+		}
+		return daysIn(2000, monthHi)
+	}
+	longest := 0
+	monthLo, _ := d.monthRange()
+	for m := monthLo; m <= monthHi; m++ {
+		longest = maxInt(longest, daysIn(d.Year, m))
+	}
+	return longest
+}
+
+// yearRange returns the earliest and latest year
+func (d *Date) yearRange() (int, int) {
+	if d.Significant > 0 {
+		digits := len(strconv.Itoa(absInt(d.Year)))
+		scale := int(math.Pow10(digits - d.Significant))
+		lo := d.Year / scale * scale
+		hi := lo + scale - 1
+		if d.Year < 0 {
+			lo, hi = lo-scale+1, lo
+		}
+		return lo, hi
+	}
+	if strings.Contains(d.yearRaw, "X") {
+		lo, _ := strconv.Atoi(strings.Replace(d.yearRaw, "X", "0", -1))
+		hi, _ := strconv.Atoi(strings.Replace(d.yearRaw, "X", "9", -1))
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		return lo, hi
+	}
+	return d.Year, d.Year
+}
+
+// monthRange returns the earliest and latest month of a month
+// precision component, seasons are handled by Earliest and Latest
+func (d *Date) monthRange() (int, int) {
+	switch {
+	case d.monthRaw == "" || d.monthRaw == "XX":
+		return 1, 12
+	case d.monthRaw[0] == 'X':
+		n := int(d.monthRaw[1] - '0')
+		switch {
+		case n == 0:
+			return 10, 10
+		case n <= 2:
+			return n, n + 10
+		}
+		return n, n
+	case d.monthRaw[1] == 'X':
+		if d.monthRaw[0] == '0' {
+			return 1, 9
+		}
+		return 10, 12
+	}
+	return d.Month, d.Month
+}
+
+// dayRange returns the earliest and latest day of the month
+func (d *Date) dayRange(year, month int) (int, int) {
+	last := daysIn(year, month)
+	switch {
+	case d.dayRaw == "" || d.dayRaw == "XX":
+		return 1, last
+	case d.dayRaw[0] == 'X':
+		n := int(d.dayRaw[1] - '0')
+		lo, hi := n, n
+		if n == 0 {
+			lo = 10
+		}
+		for _, tens := range []int{10, 20, 30} {
+			if tens+n <= last {
+				hi = tens + n
+			}
+		}
+		return lo, hi
+	case d.dayRaw[1] == 'X':
+		tens := int(d.dayRaw[0]-'0') * 10
+		lo, hi := tens, tens+9
+		if lo == 0 {
+			lo = 1
+		}
+		if hi > last {
+			hi = last
+		}
+		return lo, hi
+	}
+	// A year with unspecified digits (e.g. 20XX-02-29) includes years
+	// where the day doesn't exist
+	if d.Day > last {
+		return last, last
+	}
+	return d.Day, d.Day
+}
+
+// Earliest returns the first instant the date can refer to
+func (d *Date) Earliest() time.Time {
+	if d.hasTime {
+		return d.Time
+	}
+	year, _ := d.yearRange()
+	if d.Season > 0 {
+		return time.Date(year, time.Month(seasons[d.Season].month), 1, 0, 0, 0, 0, time.UTC)
+	}
+	month, _ := d.monthRange()
+	day, _ := d.dayRange(year, month)
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Latest returns the last instant the date can refer to
+func (d *Date) Latest() time.Time {
+	if d.hasTime {
+		return d.Time
+	}
+	_, year := d.yearRange()
+	if d.Season > 0 {
+		season := seasons[d.Season]
+		start := time.Date(year, time.Month(season.month), 1, 0, 0, 0, 0, time.UTC)
+		return start.AddDate(0, season.months, 0).Add(-time.Nanosecond)
+	}
+	_, month := d.monthRange()
+	_, day := d.dayRange(year, month)
+	return time.Date(year, time.Month(month), day, 23, 59, 59, 999999999, time.UTC)
+}
+
+// Human returns an English rendering of the date, e.g. "circa 2017",
+// "June 11, 2004" or "2010s".
+func (d *Date) Human() string {
+	var s string
+	year := d.humanYear()
+	switch {
+	case d.hasTime:
+		s = d.Time.Format("January 2, 2006 15:04:05 -07:00")
+		if d.Time.Location() == time.UTC {
+			s = d.Time.Format("January 2, 2006 15:04:05 UTC")
+		}
+	case d.Season > 0:
+		s = fmt.Sprintf("%s %s", seasons[d.Season].name, year)
+		if i := strings.Index(seasons[d.Season].name, " ("); i > 0 {
+			s = fmt.Sprintf("%s %s%s", seasons[d.Season].name[0:i], year, seasons[d.Season].name[i:])
+		}
+	case d.monthRaw == "" || d.monthRaw == "XX" && (d.dayRaw == "" || d.dayRaw == "XX"):
+		s = year
+	case strings.Contains(d.monthRaw, "X") || strings.Contains(d.yearRaw, "X"):
+		// Partially unspecified components don't read well in
+		// English, leave the EDTF form as is.
+		s = d.raw()
+	case d.dayRaw == "" || d.dayRaw == "XX":
+		s = fmt.Sprintf("%s %s", monthNames[d.Month], year)
+	case strings.Contains(d.dayRaw, "X"):
+		s = d.raw()
+	default:
+		s = fmt.Sprintf("%s %d, %s", monthNames[d.Month], d.Day, year)
+	}
+
+	// Qualifiers shared by every component are written as a prefix,
+	// otherwise each qualified component is noted after the date.
+	q := d.YearQualifier
+	uniform := true
+	for i, c := range []Qualifier{d.MonthQualifier, d.DayQualifier} {
+		if (i == 0 && d.monthRaw != "") || (i == 1 && d.dayRaw != "") {
+			if c != q {
+				uniform = false
+			}
+		}
+	}
+	if uniform {
+		return qualifierPrefix(q) + s
+	}
+	var notes []string
+	for i, c := range []Qualifier{d.YearQualifier, d.MonthQualifier, d.DayQualifier} {
+		if c != 0 {
+			notes = append(notes, fmt.Sprintf("%s %s", []string{"year", "month", "day"}[i], qualifierWord(c)))
+		}
+	}
+	return fmt.Sprintf("%s (%s)", s, strings.Join(notes, ", "))
+}
+
+// humanYear renders the year, trailing unspecified digits are written
+// as a decade or century (e.g. 201X is "2010s")
+func (d *Date) humanYear() string {
+	raw := d.yearRaw
+	if d.Significant > 0 {
+		return fmt.Sprintf("%d (%d significant digits)", d.Year, d.Significant)
+	}
+	if strings.Contains(raw, "X") {
+		trimmed := strings.TrimRight(raw, "X")
+		if strings.Contains(trimmed, "X") || trimmed == "" || strings.HasPrefix(raw, "-") {
+			return raw
+		}
+		return strings.Replace(raw, "X", "0", -1) + "s"
+	}
+	return raw
+}
+
+// raw reassembles the unqualified EDTF form of the date
+func (d *Date) raw() string {
+	parts := []string{d.yearRaw}
+	if d.monthRaw != "" {
+		parts = append(parts, d.monthRaw)
+	}
+	if d.dayRaw != "" {
+		parts = append(parts, d.dayRaw)
+	}
+	return strings.Join(parts, "-")
+}
+
+func qualifierPrefix(q Qualifier) string {
+	switch q {
+	case Uncertain:
+		return "possibly "
+	case Approximate:
+		return "circa "
+	case Uncertain | Approximate:
+		return "possibly circa "
+	}
+	return ""
+}
+
+func qualifierWord(q Qualifier) string {
+	switch q {
+	case Uncertain:
+		return "uncertain"
+	case Approximate:
+		return "approximate"
+	}
+	return "uncertain and approximate"
+}
+
+// daysIn returns the number of days in month of year
+func daysIn(year, month int) int {
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func absInt(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
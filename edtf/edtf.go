@@ -0,0 +1,321 @@
+// Package edtf parses Extended Date/Time Format (EDTF) strings as
+// described by the Library of Congress specification,
+// https://www.loc.gov/standards/datetime/. Levels 0, 1 and 2 are
+// supported. Parsed values can be rendered in a human readable form
+// and provide earliest and latest bounds suitable for sorting and
+// filtering.
+//
+// Copyright (c) 2017, Caltech
+// All rights not granted herein are expressly reserved by Caltech.
+//
+// Redistribution and use in source and binary forms, with or without modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice, this list of conditions and the following disclaimer in the documentation and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package edtf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kind identifies the shape of a parsed EDTF value
+type Kind int
+
+const (
+	// DateKind is a single date or datetime, e.g. 2004-06~
+	DateKind Kind = iota
+	// IntervalKind is a start and end separated by a slash, e.g. 1984?/2004-06
+	IntervalKind
+	// SetKind is a "one of" set, e.g. [1667,1668,1670..1672]
+	SetKind
+	// ListKind is an "all of" list, e.g. {1667,1668,1670..1672}
+	ListKind
+)
+
+// Endpoint is one end of an interval or of a range inside a set or list.
+// Open is true for "..", Unknown is true for an empty interval end.
+type Endpoint struct {
+	Date    *Date
+	Open    bool
+	Unknown bool
+}
+
+// Member is an element of a set or list. A single date has the same
+// Date in From and To, a range (e.g. 1670..1672) has different dates
+// and an open range (e.g. ..1760-12-03) has an Open endpoint.
+type Member struct {
+	From Endpoint
+	To   Endpoint
+}
+
+// EDTF holds a parsed EDTF string
+type EDTF struct {
+	// Source is the string that was parsed
+	Source string
+	// Kind is the shape of the value
+	Kind Kind
+	// Level is the lowest EDTF conformance level (0, 1 or 2) that
+	// includes every feature used by Source
+	Level int
+	// Date is set for DateKind
+	Date *Date
+	// Start and End are set for IntervalKind
+	Start Endpoint
+	End   Endpoint
+	// Members are set for SetKind and ListKind
+	Members []Member
+}
+
+// Parse takes an EDTF string and returns the parsed value or an error
+func Parse(s string) (*EDTF, error) {
+	src := strings.TrimSpace(s)
+	if src == "" {
+		return nil, fmt.Errorf("empty EDTF string")
+	}
+	e := &EDTF{Source: src}
+	var err error
+	switch {
+	case strings.HasPrefix(src, "[") && strings.HasSuffix(src, "]"):
+		e.Kind = SetKind
+		e.Level = 2
+		e.Members, err = parseMembers(src[1 : len(src)-1])
+	case strings.HasPrefix(src, "{") && strings.HasSuffix(src, "}"):
+		e.Kind = ListKind
+		e.Level = 2
+		e.Members, err = parseMembers(src[1 : len(src)-1])
+	case strings.Contains(src, "/"):
+		e.Kind = IntervalKind
+		e.Level, err = e.parseInterval(src)
+	default:
+		e.Kind = DateKind
+		e.Date, err = parseDate(src)
+		if err == nil {
+			e.Level = e.Date.level
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%q, %s", src, err)
+	}
+	return e, nil
+}
+
+// Valid returns true if s is a valid EDTF string
+func Valid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// String returns the EDTF source string
+func (e *EDTF) String() string {
+	return e.Source
+}
+
+// parseInterval parses start/end and returns the level
+func (e *EDTF) parseInterval(src string) (int, error) {
+	parts := strings.Split(src, "/")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("an interval has exactly one slash")
+	}
+	level := 0
+	for i, part := range parts {
+		var ep Endpoint
+		switch part {
+		case "":
+			ep.Unknown = true
+			level = maxInt(level, 1)
+		case "..":
+			ep.Open = true
+			level = maxInt(level, 1)
+		default:
+			d, err := parseDate(part)
+			if err != nil {
+				return 0, err
+			}
+			if d.hasTime {
+				return 0, fmt.Errorf("interval endpoints can't include a time")
+			}
+			ep.Date = d
+			level = maxInt(level, d.level)
+		}
+		if i == 0 {
+			e.Start = ep
+		} else {
+			e.End = ep
+		}
+	}
+	if e.Start.Date == nil && e.End.Date == nil {
+		return 0, fmt.Errorf("an interval needs at least one date")
+	}
+	if e.Start.Date != nil && e.End.Date != nil && e.End.Date.Latest().Before(e.Start.Date.Earliest()) {
+		return 0, fmt.Errorf("interval ends before it starts")
+	}
+	return level, nil
+}
+
+// parseMembers parses the comma separated contents of a set or list
+func parseMembers(src string) ([]Member, error) {
+	var members []Member
+	elements := strings.Split(src, ",")
+	for i, element := range elements {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			return nil, fmt.Errorf("empty element in set or list")
+		}
+		var (
+			m   Member
+			err error
+		)
+		if strings.Contains(element, "..") {
+			parts := strings.SplitN(element, "..", 2)
+			if parts[0] == "" {
+				if i != 0 {
+					return nil, fmt.Errorf("only the first element may be open at the start")
+				}
+				m.From.Open = true
+			} else if m.From.Date, err = parseDate(parts[0]); err != nil {
+				return nil, err
+			}
+			if parts[1] == "" {
+				if i != len(elements)-1 {
+					return nil, fmt.Errorf("only the last element may be open at the end")
+				}
+				m.To.Open = true
+			} else if m.To.Date, err = parseDate(parts[1]); err != nil {
+				return nil, err
+			}
+			if m.From.Open && m.To.Open {
+				return nil, fmt.Errorf("a range needs at least one date")
+			}
+			if m.From.Date != nil && m.To.Date != nil && m.To.Date.Latest().Before(m.From.Date.Earliest()) {
+				return nil, fmt.Errorf("range %s ends before it starts", element)
+			}
+		} else {
+			if m.From.Date, err = parseDate(element); err != nil {
+				return nil, err
+			}
+			m.To.Date = m.From.Date
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+// Earliest returns the earliest instant the value can refer to. The
+// boolean is false when the start is open or unknown.
+func (e *EDTF) Earliest() (time.Time, bool) {
+	switch e.Kind {
+	case DateKind:
+		return e.Date.Earliest(), true
+	case IntervalKind:
+		if e.Start.Date == nil {
+			return time.Time{}, false
+		}
+		return e.Start.Date.Earliest(), true
+	default:
+		var (
+			earliest time.Time
+			found    bool
+		)
+		for _, m := range e.Members {
+			if m.From.Open {
+				return time.Time{}, false
+			}
+			if t := m.From.Date.Earliest(); found == false || t.Before(earliest) {
+				earliest, found = t, true
+			}
+		}
+		return earliest, found
+	}
+}
+
+// Latest returns the latest instant the value can refer to. The
+// boolean is false when the end is open or unknown.
+func (e *EDTF) Latest() (time.Time, bool) {
+	switch e.Kind {
+	case DateKind:
+		return e.Date.Latest(), true
+	case IntervalKind:
+		if e.End.Date == nil {
+			return time.Time{}, false
+		}
+		return e.End.Date.Latest(), true
+	default:
+		var (
+			latest time.Time
+			found  bool
+		)
+		for _, m := range e.Members {
+			if m.To.Open {
+				return time.Time{}, false
+			}
+			if t := m.To.Date.Latest(); found == false || t.After(latest) {
+				latest, found = t, true
+			}
+		}
+		return latest, found
+	}
+}
+
+// Human returns an English rendering of the value, e.g. "circa 2017",
+// "spring 2004" or "between 1984 and June 2004".
+func (e *EDTF) Human() string {
+	switch e.Kind {
+	case DateKind:
+		return e.Date.Human()
+	case IntervalKind:
+		switch {
+		case e.Start.Open:
+			return fmt.Sprintf("until %s", e.End.Date.Human())
+		case e.Start.Unknown:
+			return fmt.Sprintf("from an unknown date until %s", e.End.Date.Human())
+		case e.End.Open:
+			return fmt.Sprintf("%s onward", e.Start.Date.Human())
+		case e.End.Unknown:
+			return fmt.Sprintf("from %s until an unknown date", e.Start.Date.Human())
+		}
+		return fmt.Sprintf("between %s and %s", e.Start.Date.Human(), e.End.Date.Human())
+	default:
+		var parts []string
+		for _, m := range e.Members {
+			switch {
+			case m.From.Open:
+				parts = append(parts, fmt.Sprintf("%s or earlier", m.To.Date.Human()))
+			case m.To.Open:
+				parts = append(parts, fmt.Sprintf("%s or later", m.From.Date.Human()))
+			case m.From.Date == m.To.Date:
+				parts = append(parts, m.From.Date.Human())
+			default:
+				parts = append(parts, fmt.Sprintf("%s to %s", m.From.Date.Human(), m.To.Date.Human()))
+			}
+		}
+		if e.Kind == SetKind {
+			if len(parts) == 1 {
+				return parts[0]
+			}
+			return "one of " + joinWords(parts, "or")
+		}
+		return joinWords(parts, "and")
+	}
+}
+
+// joinWords joins a list in English, e.g. "a, b and c"
+func joinWords(parts []string, conjunction string) string {
+	if len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	return fmt.Sprintf("%s %s %s", strings.Join(parts[0:len(parts)-1], ", "), conjunction, parts[len(parts)-1])
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,150 @@
+package edtf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLevels(t *testing.T) {
+	testSet := map[string]int{
+		// Level 0
+		"1985-04-12":                0,
+		"1985-04":                   0,
+		"1985":                      0,
+		"1985-04-12T23:20:30":       0,
+		"1985-04-12T23:20:30Z":      0,
+		"1985-04-12T23:20:30-04":    0,
+		"1985-04-12T23:20:30+04:30": 0,
+		"1964/2008":                 0,
+		"2004-02-01/2005-02-08":     0,
+		// Level 1
+		"Y170000002":    1,
+		"Y-170000002":   1,
+		"2001-21":       1,
+		"1984?":         1,
+		"2004-06~":      1,
+		"2004-06-11%":   1,
+		"201X":          1,
+		"20XX":          1,
+		"2004-XX":       1,
+		"1985-04-XX":    1,
+		"1985-XX-XX":    1,
+		"1985-04-12/..": 1,
+		"../1985-04-12": 1,
+		"1985-04-12/":   1,
+		"/1985-04-12":   1,
+		"1984~/2004-06": 1,
+		"1984?/2004-06": 1,
+		"-1985":         1,
+		// Level 2
+		"Y-17E7":                  2,
+		"1950S2":                  2,
+		"Y171010000S3":            2,
+		"2001-34":                 2,
+		"[1667,1668,1670..1672]":  2,
+		"[..1760-12-03]":          2,
+		"[1760-12..]":             2,
+		"{1667,1668,1670..1672}":  2,
+		"{1960,1961-12}":          2,
+		"2004?-06-11":             2,
+		"?2004-06-~11":            2,
+		"2004-?06-11":             2,
+		"156X-12-25":              2,
+		"15XX-12-25":              2,
+		"XXXX-12-XX":              2,
+		"1XXX-XX":                 2,
+		"1XXX-12":                 2,
+		"1984-1X":                 2,
+		"2004-06-~01/2004-06-~20": 2,
+	}
+	for s, expected := range testSet {
+		e, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q, unexpected error %s", s, err)
+			continue
+		}
+		if e.Level != expected {
+			t.Errorf("%q, expected level %d, got %d", s, expected, e.Level)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{"", "2017-13", "2017-02-30", "85", "2004-42", "2004-21-01", "2008/1964", "../..", "/", "[]", "[1667,,1668]", "2004-06-11T25:00:00", "Y2017", "1985-04-12T23:20:30?", "2004/2005/2006", "-0000", "Y1E400", "Y9E18", "Y-99999999999999999999", "Y100000000001", "Y2E11", "[1672..1670]", "{1667,1990-05..1990-04}"} {
+		if Valid(s) {
+			t.Errorf("%q, expected invalid", s)
+		}
+	}
+}
+
+func TestHuman(t *testing.T) {
+	testSet := map[string]string{
+		"2017~":                  "circa 2017",
+		"2017?":                  "possibly 2017",
+		"2004-06-11%":            "possibly circa June 11, 2004",
+		"201X":                   "2010s",
+		"2004-21":                "spring 2004",
+		"2004-33":                "first quarter of 2004",
+		"2004-29":                "spring 2004 (Southern Hemisphere)",
+		"1984/2004-06":           "between 1984 and June 2004",
+		"1984?/2004-06":          "between possibly 1984 and June 2004",
+		"1985-04-12/..":          "April 12, 1985 onward",
+		"../1985":                "until 1985",
+		"1985/":                  "from 1985 until an unknown date",
+		"[1667,1668,1670..1672]": "one of 1667, 1668 or 1670 to 1672",
+		"{1667,1668,1670..1672}": "1667, 1668 and 1670 to 1672",
+		"[..1760-12-03]":         "December 3, 1760 or earlier",
+		"2004?-06-11":            "June 11, 2004 (year uncertain)",
+		"156X-12-25":             "156X-12-25",
+		"1985-04-12T23:20:30Z":   "April 12, 1985 23:20:30 UTC",
+	}
+	for s, expected := range testSet {
+		e, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q, unexpected error %s", s, err)
+			continue
+		}
+		if r := e.Human(); r != expected {
+			t.Errorf("%q, expected %q, got %q", s, expected, r)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	day := func(y, m, d int) time.Time {
+		return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	}
+	testSet := map[string][2]time.Time{
+		"2004":                   {day(2004, 1, 1), day(2004, 12, 31)},
+		"2004-02":                {day(2004, 2, 1), day(2004, 2, 29)},
+		"201X":                   {day(2010, 1, 1), day(2019, 12, 31)},
+		"2004-24":                {day(2004, 12, 1), day(2005, 2, 28)},
+		"1985-04-1X":             {day(1985, 4, 10), day(1985, 4, 19)},
+		"1984-1X":                {day(1984, 10, 1), day(1984, 12, 31)},
+		"1950S2":                 {day(1900, 1, 1), day(1999, 12, 31)},
+		"1984?/2004-06":          {day(1984, 1, 1), day(2004, 6, 30)},
+		"[1667,1668,1670..1672]": {day(1667, 1, 1), day(1672, 12, 31)},
+		"20XX-02-29":             {day(2000, 2, 29), day(2099, 2, 28)},
+		"Y17E7":                  {day(170000000, 1, 1), day(170000000, 12, 31)},
+	}
+	for s, expected := range testSet {
+		e, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q, unexpected error %s", s, err)
+			continue
+		}
+		earliest, ok := e.Earliest()
+		if ok == false || earliest.Equal(expected[0]) == false {
+			t.Errorf("%q, expected earliest %s, got %s", s, expected[0], earliest)
+		}
+		latest, ok := e.Latest()
+		if ok == false || latest.Truncate(24*time.Hour).Equal(expected[1]) == false {
+			t.Errorf("%q, expected latest %s, got %s", s, expected[1], latest)
+		}
+	}
+
+	e, _ := Parse("1985-04-12/..")
+	if _, ok := e.Latest(); ok {
+		t.Errorf("expected an open interval to have no latest bound")
+	}
+}
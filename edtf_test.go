@@ -0,0 +1,27 @@
+package tmplfn
+
+import (
+	"testing"
+)
+
+func TestEDTFFuncs(t *testing.T) {
+	human := EDTF["edtf_human"].(func(string) string)
+	if r := human("2017~"); r != "circa 2017" {
+		t.Errorf("expected %q, got %q", "circa 2017", r)
+	}
+	earliest := EDTF["edtf_earliest"].(func(string) string)
+	latest := EDTF["edtf_latest"].(func(string) string)
+	if r := earliest("1984?/2004-06"); r != "1984-01-01" {
+		t.Errorf("expected 1984-01-01, got %q", r)
+	}
+	if r := latest("1984?/2004-06"); r != "2004-06-30" {
+		t.Errorf("expected 2004-06-30, got %q", r)
+	}
+	if r := latest("1984/.."); r != "" {
+		t.Errorf("expected empty string for an open end, got %q", r)
+	}
+	level := EDTF["edtf_level"].(func(string) int)
+	if r := level("[1667,1668,1670..1672]"); r != 2 {
+		t.Errorf("expected level 2, got %d", r)
+	}
+}
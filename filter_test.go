@@ -179,3 +179,29 @@ func TestNumericComparisons(t *testing.T) {
 		t.Errorf("expected %d, got %d", expectedCnt, cnt)
 	}
 }
+
+func TestFilterNumericComparison(t *testing.T) {
+	rec := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(`{"citations": 12, "score": "7.5", "title": "n/a"}`), &rec); err != nil {
+		t.Fatalf("%s", err)
+	}
+	testSet := map[string]bool{
+		`(num_gt .citations 10)`:                               true,
+		`(num_le .citations 10)`:                               false,
+		`(num_eq .citations 12)`:                               true,
+		`(num_between .score 5 10)`:                            true,
+		`(num_lt .title 100)`:                                  false,
+		`(and (num_ge .score 7.5) (num_ne .citations .score))`: true,
+	}
+	for src, expected := range testSet {
+		f, err := ParseFilter(src)
+		if err != nil {
+			t.Fatalf("%s, %s", src, err)
+		}
+		if r, err := f.Apply(rec); err != nil {
+			t.Errorf("%s, unexpected error %s", src, err)
+		} else if r != expected {
+			t.Errorf("%s, expected %t, got %t", src, expected, r)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package tmplfn
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFiscalYearStart is the first month of Caltech's fiscal year
+	DefaultFiscalYearStart = time.July
+	// DefaultAcademicYearStart is the first month of the academic year
+	DefaultAcademicYearStart = time.September
+)
+
+// FiscalYear returns the fiscal year containing t for a fiscal year
+// starting in start. Fiscal years are named by the calendar year they
+// end in, so with a July start 2023-07-01 is in fiscal year 2024.
+func FiscalYear(t time.Time, start time.Month) int {
+	if start <= time.January || start > time.December {
+		return t.Year()
+	}
+	if t.Month() >= start {
+		return t.Year() + 1
+	}
+	return t.Year()
+}
+
+// AcademicYear returns the calendar year an academic year starting in
+// start began in, so with a September start 2024-03-01 is in the
+// 2023 academic year.
+func AcademicYear(t time.Time, start time.Month) int {
+	if start <= time.January || start > time.December || t.Month() >= start {
+		return t.Year()
+	}
+	return t.Year() - 1
+}
+
+// AcademicYearLabel returns the label of the academic year starting
+// in year, e.g. "2023–24". An academic year starting in January is
+// labeled with the year alone.
+func AcademicYearLabel(year int, start time.Month) string {
+	if start <= time.January || start > time.December {
+		return fmt.Sprintf("%d", year)
+	}
+	return fmt.Sprintf("%d–%02d", year, (year+1)%100)
+}
+
+// Quarter returns the calendar quarter (1 to 4) of t
+func Quarter(t time.Time) int {
+	return (int(t.Month())-1)/3 + 1
+}
@@ -0,0 +1,55 @@
+package tmplfn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiscalAndAcademicYears(t *testing.T) {
+	testSet := []struct {
+		date     time.Time
+		fiscal   int
+		academic string
+	}{
+		{time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC), 2023, "2022–23"},
+		{time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), 2024, "2022–23"},
+		{time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), 2024, "2023–24"},
+		{time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), 2000, "1999–00"},
+	}
+	for _, test := range testSet {
+		if r := FiscalYear(test.date, DefaultFiscalYearStart); r != test.fiscal {
+			t.Errorf("%s, expected fiscal year %d, got %d", test.date, test.fiscal, r)
+		}
+		if r := AcademicYearLabel(AcademicYear(test.date, DefaultAcademicYearStart), DefaultAcademicYearStart); r != test.academic {
+			t.Errorf("%s, expected academic year %q, got %q", test.date, test.academic, r)
+		}
+	}
+	if r := FiscalYear(time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), time.January); r != 2023 {
+		t.Errorf("expected a January fiscal year to match the calendar year, got %d", r)
+	}
+
+	fm := TimeFuncMap(&TimeOptions{FiscalYearStart: time.October})
+	label := fm["fiscal_year_label"].(func(interface{}, ...int) string)
+	if r := label("2023-10-01"); r != "FY2024" {
+		t.Errorf("expected FY2024, got %q", r)
+	}
+	if r := label("2023-10-01", 7); r != "FY2024" {
+		t.Errorf("expected FY2024, got %q", r)
+	}
+	if r := label("2023-08-01"); r != "FY2023" {
+		t.Errorf("expected FY2023, got %q", r)
+	}
+
+	isoWeek := Time["iso_week"].(func(interface{}) int)
+	if r := isoWeek("2021-01-03"); r != 53 {
+		t.Errorf("expected week 53, got %d", r)
+	}
+	quarter := Time["quarter"].(func(interface{}) int)
+	if r := quarter("2017-08-15"); r != 3 {
+		t.Errorf("expected quarter 3, got %d", r)
+	}
+	dayOfYear := Time["day_of_year"].(func(interface{}) int)
+	if r := dayOfYear("2016-12-31"); r != 366 {
+		t.Errorf("expected day 366, got %d", r)
+	}
+}
@@ -0,0 +1,22 @@
+// Package lang holds the language code handling shared by the tmplfn
+// and numbers lookup functions (e.g. LookupLocale and LookupSpeller).
+package lang
+
+import (
+	"strings"
+)
+
+// Tags returns the keys to try, in order, when looking up the language
+// code name in a table keyed by language. The first is name as given,
+// the second its lower case base language without region or encoding
+// suffixes, so "es_MX.UTF-8" gives "es_MX.UTF-8" then "es".
+func Tags(name string) []string {
+	base := strings.ToLower(name)
+	if i := strings.IndexAny(base, "-_."); i > 0 {
+		base = base[0:i]
+	}
+	if base == name {
+		return []string{name}
+	}
+	return []string{name, base}
+}
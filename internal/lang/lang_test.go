@@ -0,0 +1,22 @@
+package lang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	testSet := map[string]string{
+		"en":          "en",
+		"es-MX":       "es-MX es",
+		"es_MX.UTF-8": "es_MX.UTF-8 es",
+		"DE":          "DE de",
+		"-x":          "-x",
+		"":            "",
+	}
+	for name, expected := range testSet {
+		if r := strings.Join(Tags(name), " "); r != expected {
+			t.Errorf("%q, expected %q, got %q", name, expected, r)
+		}
+	}
+}
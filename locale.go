@@ -0,0 +1,164 @@
+package tmplfn
+
+import (
+	"strings"
+	"time"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/internal/lang"
+)
+
+// Locale holds the month and weekday names and the date styles used to
+// format dates in a language
+type Locale struct {
+	Months        [12]string
+	ShortMonths   [12]string
+	Weekdays      [7]string
+	ShortWeekdays [7]string
+	// Styles maps the style names "full", "long", "medium" and "short"
+	// to Go layouts, names in the layouts (e.g. January, Mon) are
+	// replaced with the Locale's names
+	Styles map[string]string
+}
+
+var (
+	// Locales holds the month and weekday names and date styles used by
+	// the Time functions, keyed by lower case language code. A new
+	// entry is picked up by LookupLocale and the locale options.
+	Locales = map[string]*Locale{
+		"en": {
+			Months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+			ShortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+			Weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			ShortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+			Styles: map[string]string{
+				"full":   "Monday, January 2, 2006",
+				"long":   "January 2, 2006",
+				"medium": "Jan 2, 2006",
+				"short":  "1/2/06",
+			},
+		},
+		"es": {
+			Months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			ShortMonths:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+			Weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			ShortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+			Styles: map[string]string{
+				"full":   "Monday, 2 de January de 2006",
+				"long":   "2 de January de 2006",
+				"medium": "2 Jan 2006",
+				"short":  "2/1/06",
+			},
+		},
+		"fr": {
+			Months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			ShortMonths:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			Weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			ShortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+			Styles: map[string]string{
+				"full":   "Monday 2 January 2006",
+				"long":   "2 January 2006",
+				"medium": "2 Jan 2006",
+				"short":  "02/01/2006",
+			},
+		},
+		"de": {
+			Months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			ShortMonths:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+			Weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			ShortWeekdays: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+			Styles: map[string]string{
+				"full":   "Monday, 2. January 2006",
+				"long":   "2. January 2006",
+				"medium": "02.01.2006",
+				"short":  "02.01.06",
+			},
+		},
+		"zh": {
+			Months:        [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+			ShortMonths:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			Weekdays:      [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+			ShortWeekdays: [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+			Styles: map[string]string{
+				"full":   "2006年1月2日Monday",
+				"long":   "2006年1月2日",
+				"medium": "2006年1月2日",
+				"short":  "2006/1/2",
+			},
+		},
+	}
+
+	// nameTokens are the layout elements replaced by Locale names,
+	// longest first so January is not read as Jan
+	nameTokens = []string{"January", "Monday", "Jan", "Mon"}
+)
+
+// LookupLocale returns the Locale for a language code. Region and
+// encoding suffixes are ignored if there is no exact match, so "es-MX"
+// and "es_MX.UTF-8" both find "es".
+func LookupLocale(name string) (*Locale, bool) {
+	for _, tag := range lang.Tags(name) {
+		if l, ok := Locales[tag]; ok {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// FormatLocale formats t like t.Format(layout) but with month and
+// weekday names (January, Jan, Monday, Mon) taken from l. A nil l
+// is the same as t.Format(layout).
+func FormatLocale(t time.Time, layout string, l *Locale) string {
+	if l == nil {
+		return t.Format(layout)
+	}
+	var (
+		out   strings.Builder
+		chunk int
+	)
+	for i := 0; i < len(layout); {
+		token := ""
+		for _, name := range nameTokens {
+			if strings.HasPrefix(layout[i:], name) {
+				token = name
+				break
+			}
+		}
+		if token == "" {
+			i++
+			continue
+		}
+		if chunk < i {
+			out.WriteString(t.Format(layout[chunk:i]))
+		}
+		switch token {
+		case "January":
+			out.WriteString(l.Months[t.Month()-1])
+		case "Jan":
+			out.WriteString(l.ShortMonths[t.Month()-1])
+		case "Monday":
+			out.WriteString(l.Weekdays[t.Weekday()])
+		case "Mon":
+			out.WriteString(l.ShortWeekdays[t.Weekday()])
+		}
+		i += len(token)
+		chunk = i
+	}
+	if chunk < len(layout) {
+		out.WriteString(t.Format(layout[chunk:]))
+	}
+	return out.String()
+}
+
+// FormatLocaleStyle formats t in one of the Locale's styles ("full",
+// "long", "medium" or "short"), an unknown style uses "long".
+func FormatLocaleStyle(t time.Time, style string, l *Locale) string {
+	if l == nil {
+		l = Locales["en"]
+	}
+	layout, ok := l.Styles[style]
+	if ok == false {
+		layout = l.Styles["long"]
+	}
+	return FormatLocale(t, layout, l)
+}
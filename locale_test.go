@@ -0,0 +1,52 @@
+package tmplfn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatLocale(t *testing.T) {
+	dt := time.Date(2017, 3, 16, 14, 22, 5, 0, time.UTC)
+	testSet := []struct {
+		locale   string
+		style    string
+		expected string
+	}{
+		{"en", "full", "Thursday, March 16, 2017"},
+		{"en", "short", "3/16/17"},
+		{"es", "long", "16 de marzo de 2017"},
+		{"es-MX", "full", "jueves, 16 de marzo de 2017"},
+		{"fr", "medium", "16 mars 2017"},
+		{"de", "long", "16. März 2017"},
+		{"zh", "full", "2017年3月16日星期四"},
+		{"zh", "unknown", "2017年3月16日"},
+	}
+	for _, test := range testSet {
+		l, ok := LookupLocale(test.locale)
+		if ok == false {
+			t.Errorf("can't find locale %q", test.locale)
+			continue
+		}
+		if r := FormatLocaleStyle(dt, test.style, l); r != test.expected {
+			t.Errorf("%s %s, expected %q, got %q", test.locale, test.style, test.expected, r)
+		}
+	}
+
+	l, _ := LookupLocale("es")
+	expected := "jue 16 mar 2017 14:22"
+	if r := FormatLocale(dt, "Mon 2 Jan 2006 15:04", l); r != expected {
+		t.Errorf("expected %q, got %q", expected, r)
+	}
+
+	fm := TimeFuncMap(&TimeOptions{Locale: "zh"})
+	monthName := fm["month_name"].(func(interface{}, ...string) string)
+	if r := monthName("2017-03-16"); r != "三月" {
+		t.Errorf("expected 三月, got %q", r)
+	}
+	if r := monthName("2017-03-16", "de"); r != "März" {
+		t.Errorf("expected März, got %q", r)
+	}
+	if r := monthName("2017-03-16", "tlh"); r != "" {
+		t.Errorf("expected an empty string for an unknown locale, got %q", r)
+	}
+}
@@ -0,0 +1,317 @@
+package tmplfn
+
+import (
+	"fmt"
+	"text/template"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/dotpath"
+	"github.com/caltechlibrary/tmplfn/numbers"
+)
+
+// MathOptions holds the settings used by MathFuncMap to build a set of
+// Math functions.
+type MathOptions struct {
+	// Lenient restores the original behavior of the arithmetic
+	// functions, values that aren't numbers are treated as zero and
+	// dividing by zero returns zero. By default they return an error
+	// so a template's Execute fails. Lenient functions can't return an
+	// error so OverflowError and NaNError give zero, as does calc for
+	// an invalid expression, and ordinal, roman and spell render a
+	// value they can't convert as is.
+	Lenient bool
+
+	// Overflow decides what add, sub, multiply, divide and pow do
+	// when an integer result overflows, the zero value is
+	// numbers.OverflowError
+	Overflow numbers.OverflowPolicy
+
+	// NaN decides what the arithmetic and rounding functions do with
+	// NaN and infinite results, the zero value is numbers.NaNError
+	NaN numbers.NaNPolicy
+
+	// Placeholder is rendered for NaN and infinite results when NaN is
+	// numbers.NaNPlaceholder
+	Placeholder string
+}
+
+// MathFuncMap returns the Math functions configured by opts, a nil opts
+// returns the same functions as Math. E.g. promote integer overflow to
+// float64 and render NaN and infinite results as "n/a",
+//
+//	fm := tmplfn.Join(tmplfn.AllFuncs(), tmplfn.MathFuncMap(&tmplfn.MathOptions{
+//		Overflow:    numbers.OverflowFloat,
+//		NaN:         numbers.NaNPlaceholder,
+//		Placeholder: "n/a",
+//	}))
+func MathFuncMap(opts *MathOptions) template.FuncMap {
+	p := numbers.Policy{Strict: true}
+	if opts != nil {
+		p = numbers.Policy{
+			Overflow:    opts.Overflow,
+			NaN:         opts.NaN,
+			Placeholder: opts.Placeholder,
+			Strict:      opts.Lenient == false,
+		}
+	}
+	fm := template.FuncMap{
+		// The num_ comparisons promote mixed types like add does so a
+		// float64 from JSON can be compared with an int literal (e.g.
+		// num_gt .citations 10), values that aren't numbers only
+		// satisfy num_ne
+		"num_eq":      numbers.Equal,
+		"num_ne":      numbers.NotEqual,
+		"num_lt":      numbers.Less,
+		"num_le":      numbers.LessOrEqual,
+		"num_gt":      numbers.Greater,
+		"num_ge":      numbers.GreaterOrEqual,
+		"num_between": numbers.Between,
+		// is_numeric is true for numbers and numeric strings like "1,024"
+		"is_numeric": numbers.IsNumeric,
+		// The aggregates take a list and an optional dot path to pluck
+		// from each element (e.g. sum .records ".citations"), elements
+		// that aren't numbers, NaN and infinities are skipped (as in Stats)
+		"count": func(list interface{}, p ...string) int {
+			return numbers.Count(pluck(list, p))
+		},
+		"mean": func(list interface{}, p ...string) float64 {
+			return numbers.Mean(pluck(list, p))
+		},
+		"median": func(list interface{}, p ...string) interface{} {
+			return numbers.Median(pluck(list, p))
+		},
+		"mode": func(list interface{}, p ...string) interface{} {
+			return numbers.Mode(pluck(list, p))
+		},
+		"minimum": func(list interface{}, p ...string) interface{} {
+			return numbers.Minimum(pluck(list, p))
+		},
+		"maximum": func(list interface{}, p ...string) interface{} {
+			return numbers.Maximum(pluck(list, p))
+		},
+		"typeof": func(t interface{}) string {
+			if t == nil {
+				return "<nil>"
+			}
+			return fmt.Sprintf("%T", t)
+		},
+	}
+	if p.Strict {
+		return Join(fm, strictMathFuncs(p))
+	}
+	return Join(fm, lenientMathFuncs(p))
+}
+
+// orZero returns v or zero if err isn't nil
+func orZero(v interface{}, err error) interface{} {
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// orInput returns s or v rendered as a string if err isn't nil
+func orInput(v interface{}, s string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return s
+}
+
+// dataLookup returns a calc Lookup for the names in the optional data
+// map, dotted names (e.g. order.discount) reach into nested maps
+func dataLookup(data []interface{}) numbers.Lookup {
+	if len(data) == 0 {
+		return nil
+	}
+	return func(name string) (interface{}, bool) {
+		v, err := dotpath.Eval("."+name, data[0])
+		return v, err == nil
+	}
+}
+
+// spell spells out v in the optional language lang
+func spell(v interface{}, lang []string) (string, error) {
+	if len(lang) > 0 {
+		return numbers.Spell(v, lang[0])
+	}
+	return numbers.Spell(v, "")
+}
+
+// withResult applies p's NaN policy to the result of fn
+func withResult(p numbers.Policy, fn func(interface{}) (interface{}, error)) func(interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		r, err := fn(v)
+		if err != nil {
+			return nil, err
+		}
+		return p.Result(r)
+	}
+}
+
+// strictMathFuncs returns the arithmetic functions that return an
+// error for values that aren't numbers
+func strictMathFuncs(p numbers.Policy) template.FuncMap {
+	return template.FuncMap{
+		"int":      numbers.IntErr,
+		"int64":    numbers.Int64Err,
+		"uint64":   numbers.Uint64Err,
+		"float32":  numbers.Float32Err,
+		"float64":  numbers.Float64Err,
+		"add":      p.Add,
+		"sub":      p.Subtract,
+		"multiply": p.Multiply,
+		"divide":   p.Divide,
+		"modulo":   p.Modulo,
+		"addi":     numbers.AddiErr,
+		"subi":     numbers.SubiErr,
+		// round takes an optional precision (default 0) and rounding mode
+		// (default "half_up", "half_even" or "bankers" for banker's
+		// rounding), e.g. round 2.675 2 is 2.68. An unknown mode or a
+		// precision beyond ±numbers.MaxDecimalExponent is an error, as
+		// it is for dround.
+		"round": func(v interface{}, args ...interface{}) (interface{}, error) {
+			precision, mode := 0, numbers.HalfUp
+			var err error
+			if len(args) > 0 {
+				if precision, err = numbers.IntErr(args[0]); err != nil {
+					return nil, fmt.Errorf("round: %s", err)
+				}
+			}
+			if len(args) > 1 {
+				if mode, err = numbers.ParseRoundingMode(fmt.Sprintf("%v", args[1])); err != nil {
+					return nil, fmt.Errorf("round: %s", err)
+				}
+			}
+			r, err := numbers.RoundErr(v, precision, mode)
+			if err != nil {
+				return nil, err
+			}
+			return p.Result(r)
+		},
+		"floor": withResult(p, numbers.FloorValueErr),
+		"ceil":  withResult(p, numbers.CeilValueErr),
+		"abs":   withResult(p, numbers.AbsErr),
+		"pow":   p.Pow,
+		"sqrt":  p.Sqrt,
+		// min and max take two or more values, clamp takes a value, a low and a high
+		"min":   numbers.MinErr,
+		"max":   numbers.MaxErr,
+		"clamp": numbers.ClampErr,
+		// The d functions do exact decimal arithmetic, results are json.Number
+		// values (e.g. dadd "0.1" "0.2" is 0.3), ddiv rounds to
+		// numbers.DefaultDecimalContext, dround takes a scale and optional
+		// rounding mode (e.g. "half_even")
+		"decimal": numbers.ToDecimalErr,
+		"dadd":    numbers.DAddErr,
+		"dsub":    numbers.DSubtractErr,
+		"dmul":    numbers.DMultiplyErr,
+		"ddiv":    numbers.DDivideErr,
+		"dround":  numbers.DRoundErr,
+		"dcmp":    numbers.DCompareErr,
+		// calc evaluates an expression with variables taken from an optional
+		// map (usually the current dot), e.g. calc "price * qty + shipping / 2" .
+		// Dotted names (e.g. order.discount) reach into nested maps.
+		"calc": func(expr string, data ...interface{}) (interface{}, error) {
+			return p.Calc(expr, dataLookup(data))
+		},
+		// sum follows the overflow and NaN policies like add
+		"sum": func(list interface{}, path ...string) (interface{}, error) {
+			return p.Sum(pluck(list, path))
+		},
+		// ordinal, roman and spell render integers for display, e.g.
+		// ordinal 2 is "2nd", roman 14 is "XIV" and spell 21 is
+		// "twenty-one". spell takes an optional language code (default
+		// "en", see numbers.Spellers).
+		"ordinal":     numbers.Ordinal,
+		"roman":       numbers.Roman,
+		"parse_roman": numbers.ParseRoman,
+		"spell": func(v interface{}, lang ...string) (string, error) {
+			return spell(v, lang)
+		},
+	}
+}
+
+// lenientMathFuncs returns the original arithmetic functions, values
+// that aren't numbers are treated as zero. None of them return an
+// error, calc, sum and parse_roman give zero and ordinal, roman and
+// spell render a value they can't convert as is.
+func lenientMathFuncs(p numbers.Policy) template.FuncMap {
+	binary := func(fn func(interface{}, interface{}) (interface{}, error)) func(interface{}, interface{}) interface{} {
+		return func(v1, v2 interface{}) interface{} {
+			return orZero(fn(v1, v2))
+		}
+	}
+	unary := func(fn func(interface{}) interface{}) func(interface{}) interface{} {
+		return func(v interface{}) interface{} {
+			return orZero(p.Result(fn(v)))
+		}
+	}
+	return template.FuncMap{
+		"int":      numbers.Int,
+		"int64":    numbers.Int64,
+		"uint64":   numbers.Uint64,
+		"float32":  numbers.Float32,
+		"float64":  numbers.Float64,
+		"add":      binary(p.Add),
+		"sub":      binary(p.Subtract),
+		"multiply": binary(p.Multiply),
+		"divide":   binary(p.Divide),
+		"modulo":   binary(p.Modulo),
+		"addi":     numbers.Addi,
+		"subi":     numbers.Subi,
+		// The lenient round rounds half_up for an unknown mode, round
+		// and dround clamp a precision beyond ±numbers.MaxDecimalExponent
+		// to it (so dround 2.5 1000000000 has 10000 decimal places) and
+		// dround gives 0 for an unknown mode
+		"round": func(v interface{}, args ...interface{}) interface{} {
+			precision, mode := 0, numbers.HalfUp
+			if len(args) > 0 {
+				precision = numbers.Int(args[0])
+			}
+			if len(args) > 1 {
+				mode, _ = numbers.ParseRoundingMode(fmt.Sprintf("%v", args[1]))
+			}
+			return orZero(p.Result(numbers.Round(v, precision, mode)))
+		},
+		"floor": unary(numbers.FloorValue),
+		"ceil":  unary(numbers.CeilValue),
+		"abs":   unary(numbers.Abs),
+		"pow":   binary(p.Pow),
+		"sqrt": func(v interface{}) interface{} {
+			return orZero(p.Sqrt(v))
+		},
+		"min":     numbers.Min,
+		"max":     numbers.Max,
+		"clamp":   numbers.Clamp,
+		"decimal": numbers.ToDecimal,
+		"dadd":    numbers.DAdd,
+		"dsub":    numbers.DSubtract,
+		"dmul":    numbers.DMultiply,
+		"ddiv":    numbers.DDivide,
+		"dround":  numbers.DRound,
+		"dcmp":    numbers.DCompare,
+		"calc": func(expr string, data ...interface{}) interface{} {
+			return orZero(p.Calc(expr, dataLookup(data)))
+		},
+		"sum": func(list interface{}, path ...string) interface{} {
+			return orZero(p.Sum(pluck(list, path)))
+		},
+		"ordinal": func(v interface{}) string {
+			s, err := numbers.Ordinal(v)
+			return orInput(v, s, err)
+		},
+		"roman": func(v interface{}) string {
+			s, err := numbers.Roman(v)
+			return orInput(v, s, err)
+		},
+		"parse_roman": func(s string) int {
+			n, _ := numbers.ParseRoman(s)
+			return n
+		},
+		"spell": func(v interface{}, lang ...string) string {
+			s, err := spell(v, lang)
+			return orInput(v, s, err)
+		},
+	}
+}
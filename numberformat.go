@@ -0,0 +1,77 @@
+package tmplfn
+
+import (
+	"fmt"
+	"text/template"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/numbers"
+)
+
+var (
+	// NumberFormat provides number, percent and currency formatting
+	// using English conventions. Use NumberFormatFuncMap for another
+	// language.
+	NumberFormat = NumberFormatFuncMap("en")
+)
+
+// optionalInt returns the first of values as an int or fallback if
+// values is empty
+func optionalInt(values []interface{}, fallback int) int {
+	if len(values) == 0 {
+		return fallback
+	}
+	return numbers.Int(values[0])
+}
+
+// NumberFormatFuncMap returns the number formatting functions for a
+// language in numbers.NumberLocales (e.g. "de" or "fr-CA"), unknown
+// languages use English.
+func NumberFormatFuncMap(locale string) template.FuncMap {
+	l, ok := numbers.LookupNumberLocale(locale)
+	if ok == false {
+		l = numbers.NumberLocales["en"]
+	}
+	return template.FuncMap{
+		// format_number groups the digits of v and optionally rounds
+		// to a number of decimal places, e.g. format_number 1234567.891 2
+		// is 1,234,567.89
+		"format_number": func(v interface{}, decimals ...interface{}) string {
+			return numbers.FormatNumber(v, optionalInt(decimals, -1), l)
+		},
+		// format_significant rounds v to a number of significant digits
+		"format_significant": func(v interface{}, digits interface{}) string {
+			return numbers.FormatSignificant(v, numbers.Int(digits), l)
+		},
+		// format_compact abbreviates v, e.g. 1234567 is 1.2M
+		"format_compact": func(v interface{}, decimals ...interface{}) string {
+			return numbers.FormatCompact(v, optionalInt(decimals, -1), l)
+		},
+		// format_percent renders a fraction as a percent, e.g. 0.125 is 12.5%
+		"format_percent": func(v interface{}, decimals ...interface{}) string {
+			return numbers.FormatPercent(v, optionalInt(decimals, -1), l)
+		},
+		// format_currency takes an ISO 4217 code (e.g. "USD") or a
+		// symbol, e.g. format_currency 1234.5 "EUR" is €1,234.50
+		"format_currency": func(v interface{}, currency string, decimals ...interface{}) string {
+			return numbers.FormatCurrency(v, currency, optionalInt(decimals, -1), l)
+		},
+		// format_currency_compact abbreviates an amount, e.g. $1.2M
+		"format_currency_compact": func(v interface{}, currency string, decimals ...interface{}) string {
+			return numbers.FormatCurrencyCompact(v, currency, optionalInt(decimals, -1), l)
+		},
+		// format_bytes renders a byte count in SI units, e.g. 1500000 is
+		// 1.5 MB, an optional precision sets the decimal places
+		"format_bytes": func(v interface{}, precision ...interface{}) string {
+			return numbers.FormatBytes(v, optionalInt(precision, -1), l)
+		},
+		// format_bytes_iec renders a byte count in IEC units, e.g. 1500000 is 1.4 MiB
+		"format_bytes_iec": func(v interface{}, precision ...interface{}) string {
+			return numbers.FormatBytesIEC(v, optionalInt(precision, -1), l)
+		},
+		// parse_bytes turns a size like "1.5 MB" or "2 GiB" back into bytes
+		"parse_bytes": func(v interface{}) (interface{}, error) {
+			return numbers.ParseBytes(fmt.Sprintf("%v", v))
+		},
+	}
+}
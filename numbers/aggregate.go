@@ -0,0 +1,137 @@
+package numbers
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+)
+
+// Values returns the numeric elements of a slice or array as numbers,
+// numeric strings are parsed (see ParseNumber) and everything else,
+// including NaN and infinities, is skipped. A nil or non-slice value
+// returns an empty list. The aggregates here and in the stats package
+// all work from Values so they skip the same elements.
+func Values(list interface{}) []interface{} {
+	values := []interface{}{}
+	if list == nil {
+		return values
+	}
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return values
+	}
+	for i := 0; i < rv.Len(); i++ {
+		v := rv.Index(i).Interface()
+		switch x := v.(type) {
+		case string:
+			if n, err := ParseNumber(x); err == nil && isFinite(n) {
+				values = append(values, n)
+			}
+		default:
+			if IsNumber(x) && isFinite(x) {
+				values = append(values, x)
+			}
+		}
+	}
+	return values
+}
+
+// isFinite is false for NaN and infinite float values
+func isFinite(v interface{}) bool {
+	if _, ok := v.(*big.Int); ok {
+		return true
+	}
+	f := Float64(v)
+	return math.IsNaN(f) == false && math.IsInf(f, 0) == false
+}
+
+// sorted returns the numeric values of list in ascending order
+func sorted(list interface{}) []interface{} {
+	values := Values(list)
+	sort.SliceStable(values, func(i, j int) bool {
+		return Compare(values[i], values[j]) < 0
+	})
+	return values
+}
+
+// Sum adds the numeric values in list, an empty list sums to zero
+func Sum(list interface{}) interface{} {
+	var total interface{} = 0
+	for _, v := range Values(list) {
+		total = Add(total, v)
+	}
+	return total
+}
+
+// Count returns the number of numeric values in list
+func Count(list interface{}) int {
+	return len(Values(list))
+}
+
+// Mean returns the average of the numeric values in list or zero
+func Mean(list interface{}) float64 {
+	values := Values(list)
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += Float64(v)
+	}
+	return total / float64(len(values))
+}
+
+// Median returns the middle of the numeric values in list. For an even
+// number of values it is the float64 mean of the two middle values. An
+// empty list returns zero.
+func Median(list interface{}) interface{} {
+	values := sorted(list)
+	n := len(values)
+	switch {
+	case n == 0:
+		return 0
+	case n%2 == 1:
+		return values[n/2]
+	}
+	return (Float64(values[n/2-1]) + Float64(values[n/2])) / 2
+}
+
+// Mode returns the most frequent numeric value in list, ties go to the
+// smallest value. An empty list returns zero.
+func Mode(list interface{}) interface{} {
+	values := sorted(list)
+	if len(values) == 0 {
+		return 0
+	}
+	mode, best := values[0], 0
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && Compare(values[i], values[j]) == 0 {
+			j++
+		}
+		if j-i > best {
+			mode, best = values[i], j-i
+		}
+		i = j
+	}
+	return mode
+}
+
+// Minimum returns the smallest numeric value in list or zero
+func Minimum(list interface{}) interface{} {
+	values := sorted(list)
+	if len(values) == 0 {
+		return 0
+	}
+	return values[0]
+}
+
+// Maximum returns the largest numeric value in list or zero
+func Maximum(list interface{}) interface{} {
+	values := sorted(list)
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
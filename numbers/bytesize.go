@@ -0,0 +1,117 @@
+package numbers
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var (
+	// siByteUnits are powers of 1000, iecByteUnits are powers of 1024
+	siByteUnits  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
+	iecByteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"}
+
+	reByteSize = regexp.MustCompile(`^([+-]?[0-9][0-9,]*(\.[0-9]*)?|[+-]?\.[0-9]+)\s*([a-zA-Z]*)$`)
+)
+
+// formatBytes renders v in units that are powers of base
+func formatBytes(v interface{}, precision int, base int64, units []string, l *NumberLocale) string {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	l = numberLocale(l)
+	abs := d
+	if d.Sign() < 0 {
+		abs = negate(d)
+	}
+	b := NewDecimal(base, 0)
+	i, unit := 0, NewDecimal(1, 0)
+	for i+1 < len(units) && abs.Cmp(unit.Mul(b)) >= 0 {
+		unit = unit.Mul(b)
+		i++
+	}
+	for {
+		q, _ := d.Div(unit, 16, HalfEven)
+		switch {
+		case i == 0:
+			q = q.Round(0, HalfEven)
+		case precision < 0:
+			q = q.Round(1, HalfEven).trim()
+		default:
+			q = q.Round(precision, HalfEven)
+		}
+		// Rounding up can reach the next unit, e.g. 999999 bytes is 1 MB
+		if i+1 < len(units) && (q.Cmp(b) >= 0 || negate(q).Cmp(b) >= 0) {
+			unit = unit.Mul(b)
+			i++
+			continue
+		}
+		s, negative := render(q, l)
+		return withSign(s+" "+units[i], negative)
+	}
+}
+
+// FormatBytes renders a byte count in SI units (powers of 1000), e.g.
+// 1500000 is "1.5 MB". Precision is the number of decimal places, a
+// negative precision shows up to one. Values that aren't numbers are
+// returned as is.
+func FormatBytes(v interface{}, precision int, l *NumberLocale) string {
+	return formatBytes(v, precision, 1000, siByteUnits, l)
+}
+
+// FormatBytesIEC renders a byte count in IEC units (powers of 1024),
+// e.g. 1500000 is "1.4 MiB"
+func FormatBytesIEC(v interface{}, precision int, l *NumberLocale) string {
+	return formatBytes(v, precision, 1024, iecByteUnits, l)
+}
+
+// ParseBytes parses a size such as "1.5 MB", "1.4 MiB", "2G" or
+// "1,024 bytes" and returns the number of bytes, rounded to a whole
+// byte, as an int64 or as a float64 if it is too large. Units are case
+// insensitive, single letters (K, M, G, ...) and SI units are powers of
+// 1000 and IEC units (KiB, MiB, ...) are powers of 1024. A number
+// without a unit is bytes.
+func ParseBytes(s string) (interface{}, error) {
+	m := reByteSize.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, fmt.Errorf("can't parse byte size %q", s)
+	}
+	d, err := ParseDecimal(m[1])
+	if err != nil {
+		return nil, fmt.Errorf("can't parse byte size %q", s)
+	}
+	multiplier, ok := byteMultiplier(m[3])
+	if ok == false {
+		return nil, fmt.Errorf("unknown unit %q in byte size %q", m[3], s)
+	}
+	n := d.Mul(Decimal{unscaled: multiplier}).Round(0, HalfUp)
+	if n.unscaled.IsInt64() {
+		return n.unscaled.Int64(), nil
+	}
+	return n.Float64(), nil
+}
+
+// byteMultiplier returns the number of bytes in unit
+func byteMultiplier(unit string) (*big.Int, bool) {
+	u := strings.ToLower(unit)
+	switch u {
+	case "", "b", "byte", "bytes":
+		return big.NewInt(1), true
+	}
+	prefixes := "kmgtpezy"
+	i := strings.IndexByte(prefixes, u[0])
+	if i < 0 {
+		return nil, false
+	}
+	base := int64(1000)
+	switch u[1:] {
+	case "", "b":
+	case "ib":
+		base = 1024
+	default:
+		return nil, false
+	}
+	return new(big.Int).Exp(big.NewInt(base), big.NewInt(int64(i+1)), nil), true
+}
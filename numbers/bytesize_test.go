@@ -0,0 +1,58 @@
+package numbers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFormatBytes(t *testing.T) {
+	testSet := []struct {
+		r, expected string
+	}{
+		{FormatBytes(1500000, -1, nil), "1.5 MB"},
+		{FormatBytesIEC(1500000, -1, nil), "1.4 MiB"},
+		{FormatBytes(json.Number("1000"), -1, nil), "1 kB"},
+		{FormatBytes(999, 2, nil), "999 B"},
+		{FormatBytes(999999, -1, nil), "1 MB"},
+		{FormatBytes("1536", 2, nil), "1.54 kB"},
+		{FormatBytesIEC(1536, 2, nil), "1.50 KiB"},
+		{FormatBytesIEC(uint64(1)<<62, -1, nil), "4 EiB"},
+		{FormatBytes(-2500, -1, nil), "-2.5 kB"},
+		{FormatBytes(1234567, 2, NumberLocales["de"]), "1,23 MB"},
+		{FormatBytes("n/a", 1, nil), "n/a"},
+	}
+	for i, test := range testSet {
+		if test.r != test.expected {
+			t.Errorf("(%d) expected %q, got %q", i, test.expected, test.r)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	testSet := []struct {
+		src      string
+		expected interface{}
+	}{
+		{"1.5 MB", int64(1500000)},
+		{"1.4 MiB", int64(1468006)},
+		{"2G", int64(2000000000)},
+		{"2 gib", int64(2147483648)},
+		{"1,024 bytes", int64(1024)},
+		{"42", int64(42)},
+		{".5kB", int64(500)},
+		{"10 YB", 1e25},
+	}
+	for _, test := range testSet {
+		r, err := ParseBytes(test.src)
+		if err != nil {
+			t.Errorf("%q, unexpected error %s", test.src, err)
+		} else if r != test.expected {
+			t.Errorf("%q, expected %T %v, got %T %v", test.src, test.expected, test.expected, r, r)
+		}
+	}
+	for _, src := range []string{"", "MB", "1.5 MX", "1.5 megabytes", "1,00 kB", "one kB"} {
+		if r, err := ParseBytes(src); err == nil {
+			t.Errorf("%q, expected an error, got %v", src, r)
+		}
+	}
+}
@@ -0,0 +1,492 @@
+package numbers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// maxCalcDepth limits how deeply a Calc expression may nest
+const maxCalcDepth = 64
+
+// Lookup returns the value of a variable in a Calc expression and
+// whether it exists
+type Lookup func(name string) (interface{}, bool)
+
+// MapLookup returns a Lookup for a map. Dotted names (e.g. item.price)
+// are looked up in nested maps.
+func MapLookup(vars map[string]interface{}) Lookup {
+	return func(name string) (interface{}, bool) {
+		var v interface{} = vars
+		for _, key := range strings.Split(name, ".") {
+			m, ok := v.(map[string]interface{})
+			if ok == false {
+				return nil, false
+			}
+			if v, ok = m[key]; ok == false {
+				return nil, false
+			}
+		}
+		return v, true
+	}
+}
+
+// calcFuncs are the functions available in Calc expressions
+var calcFuncs = map[string]func(args []interface{}) (interface{}, error){
+	"round": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, fmt.Errorf("round takes one or two arguments")
+		}
+		precision := 0
+		if len(args) == 2 {
+			precision = Int(args[1])
+		}
+		return RoundErr(args[0], precision, HalfUp)
+	},
+	"floor": unaryCalcFunc("floor", FloorValueErr),
+	"ceil":  unaryCalcFunc("ceil", CeilValueErr),
+	"abs":   unaryCalcFunc("abs", AbsErr),
+	"sqrt": unaryCalcFunc("sqrt", func(v interface{}) (interface{}, error) {
+		return SqrtErr(v)
+	}),
+	"pow": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("pow takes two arguments")
+		}
+		return PowErr(args[0], args[1])
+	},
+	"min": func(args []interface{}) (interface{}, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("min takes two or more arguments")
+		}
+		return MinErr(args[0], args[1], args[2:]...)
+	},
+	"max": func(args []interface{}) (interface{}, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("max takes two or more arguments")
+		}
+		return MaxErr(args[0], args[1], args[2:]...)
+	},
+	"clamp": func(args []interface{}) (interface{}, error) {
+		if len(args) != 3 {
+			return nil, fmt.Errorf("clamp takes three arguments")
+		}
+		return ClampErr(args[0], args[1], args[2])
+	},
+}
+
+// unaryCalcFunc wraps a one argument function for calcFuncs
+func unaryCalcFunc(name string, fn func(interface{}) (interface{}, error)) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s takes one argument", name)
+		}
+		return fn(args[0])
+	}
+}
+
+// Calc evaluates an infix expression such as "price * qty + shipping / 2"
+// using the same promotion rules as Add, Multiply, etc. Variables are
+// resolved with lookup, which may be nil if there are none. Expressions
+// support
+//
+//	numbers (42, 2.5, 1e3), true and false
+//	parentheses and unary minus, plus and not (!)
+//	^ (power), *, /, %, + and -
+//	==, !=, <, <=, > and >= which give true or false
+//	&& and || on true and false values
+//	round, floor, ceil, abs, pow, sqrt, min, max and clamp
+//
+// Division by zero, integer overflow, NaN and infinite results, unknown
+// variables and values that aren't numbers are errors. Use Policy.Calc
+// to choose other overflow and NaN policies.
+func Calc(expr string, lookup Lookup) (interface{}, error) {
+	return Policy{Strict: true}.Calc(expr, lookup)
+}
+
+// Calc evaluates expr like Calc applying the overflow and NaN
+// policies, values that aren't numbers and division by zero are always
+// errors
+func (policy Policy) Calc(expr string, lookup Lookup) (interface{}, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	policy.Strict = true
+	p := &calcParser{tokens: tokens, lookup: lookup, policy: policy}
+	v, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in %q", p.tokens[p.pos].text, expr)
+	}
+	return v, nil
+}
+
+// calcToken is a lexical token of a Calc expression
+type calcToken struct {
+	kind rune // 'n' number, 'i' identifier, 'o' operator
+	text string
+}
+
+// tokenize splits expr into numbers, identifiers and operators
+func tokenize(expr string) ([]calcToken, error) {
+	var tokens []calcToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			// exponent, e.g. 1e3 or 2.5E-4
+			if j < len(runes) && (runes[j] == 'e' || runes[j] == 'E') {
+				k := j + 1
+				if k < len(runes) && (runes[k] == '+' || runes[k] == '-') {
+					k++
+				}
+				if k < len(runes) && unicode.IsDigit(runes[k]) {
+					for j = k; j < len(runes) && unicode.IsDigit(runes[j]); j++ {
+					}
+				}
+			}
+			tokens = append(tokens, calcToken{'n', string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, calcToken{'i', strings.TrimSuffix(string(runes[i:j]), ".")})
+			i = j
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = two
+				}
+			}
+			if strings.Contains("+-*/%^()<>!,", op) == false && len(op) == 1 {
+				return nil, fmt.Errorf("unexpected %q in %q", op, expr)
+			}
+			tokens = append(tokens, calcToken{'o', op})
+			i += len([]rune(op))
+		}
+	}
+	return tokens, nil
+}
+
+// calcParser is a recursive descent parser that evaluates as it parses
+type calcParser struct {
+	tokens []calcToken
+	pos    int
+	depth  int
+	lookup Lookup
+	policy Policy
+}
+
+// accept consumes the next token if it is one of the operators ops
+func (p *calcParser) accept(ops ...string) (string, bool) {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == 'o' {
+		for _, op := range ops {
+			if p.tokens[p.pos].text == op {
+				p.pos++
+				return op, true
+			}
+		}
+	}
+	return "", false
+}
+
+// number checks v is a number, it is an error to do arithmetic on
+// true or false
+func number(op string, v interface{}) (interface{}, error) {
+	if _, ok := v.(bool); ok || IsNumeric(v) == false {
+		return nil, fmt.Errorf("%s needs a number, got %v", op, v)
+	}
+	return v, nil
+}
+
+// boolean checks v is true or false
+func boolean(op string, v interface{}) (bool, error) {
+	b, ok := v.(bool)
+	if ok == false {
+		return false, fmt.Errorf("%s needs true or false, got %v", op, v)
+	}
+	return b, nil
+}
+
+func (p *calcParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	for err == nil {
+		if _, ok := p.accept("||"); ok == false {
+			break
+		}
+		var right interface{}
+		if right, err = p.parseAnd(); err != nil {
+			break
+		}
+		var a, b bool
+		if a, err = boolean("||", left); err != nil {
+			break
+		}
+		if b, err = boolean("||", right); err != nil {
+			break
+		}
+		left = a || b
+	}
+	return left, err
+}
+
+func (p *calcParser) parseAnd() (interface{}, error) {
+	left, err := p.parseComparison()
+	for err == nil {
+		if _, ok := p.accept("&&"); ok == false {
+			break
+		}
+		var right interface{}
+		if right, err = p.parseComparison(); err != nil {
+			break
+		}
+		var a, b bool
+		if a, err = boolean("&&", left); err != nil {
+			break
+		}
+		if b, err = boolean("&&", right); err != nil {
+			break
+		}
+		left = a && b
+	}
+	return left, err
+}
+
+func (p *calcParser) parseComparison() (interface{}, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if ok == false {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if a, ok := left.(bool); ok && (op == "==" || op == "!=") {
+		b, err := boolean(op, right)
+		if err != nil {
+			return nil, err
+		}
+		return (a == b) == (op == "=="), nil
+	}
+	if _, err = number(op, left); err != nil {
+		return nil, err
+	}
+	if _, err = number(op, right); err != nil {
+		return nil, err
+	}
+	switch op {
+	case "==":
+		return Equal(left, right), nil
+	case "!=":
+		return NotEqual(left, right), nil
+	case "<":
+		return Less(left, right), nil
+	case "<=":
+		return LessOrEqual(left, right), nil
+	case ">":
+		return Greater(left, right), nil
+	}
+	return GreaterOrEqual(left, right), nil
+}
+
+func (p *calcParser) parseSum() (interface{}, error) {
+	left, err := p.parseProduct()
+	for err == nil {
+		op, ok := p.accept("+", "-")
+		if ok == false {
+			break
+		}
+		var right interface{}
+		if right, err = p.parseProduct(); err != nil {
+			break
+		}
+		if left, err = p.arithmetic(op, left, right); err != nil {
+			break
+		}
+	}
+	return left, err
+}
+
+func (p *calcParser) parseProduct() (interface{}, error) {
+	left, err := p.parseUnary()
+	for err == nil {
+		op, ok := p.accept("*", "/", "%")
+		if ok == false {
+			break
+		}
+		var right interface{}
+		if right, err = p.parseUnary(); err != nil {
+			break
+		}
+		if left, err = p.arithmetic(op, left, right); err != nil {
+			break
+		}
+	}
+	return left, err
+}
+
+func (p *calcParser) parseUnary() (interface{}, error) {
+	// Parentheses, unary operators and powers all recurse through here
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxCalcDepth {
+		return nil, fmt.Errorf("expression is nested too deeply")
+	}
+	op, ok := p.accept("-", "+", "!")
+	if ok == false {
+		return p.parsePower()
+	}
+	v, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "!":
+		b, err := boolean(op, v)
+		return !b, err
+	case "-":
+		return p.arithmetic(op, 0, v)
+	}
+	return number(op, v)
+}
+
+func (p *calcParser) parsePower() (interface{}, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("^"); ok == false {
+		return base, nil
+	}
+	// ^ is right associative and binds tighter than unary minus on
+	// its left, so -2^2 is -4 and 2^-1 is 0.5
+	exp, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return p.arithmetic("^", base, exp)
+}
+
+func (p *calcParser) parsePrimary() (interface{}, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case 'n':
+		// integer literals are ints like they are in templates
+		n, err := ParseNumber(t.text)
+		if i, ok := n.(int64); ok && int64(int(i)) == i {
+			return int(i), nil
+		}
+		return n, err
+	case 'i':
+		switch t.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t.text)
+		}
+		if p.lookup != nil {
+			if v, ok := p.lookup(t.text); ok {
+				if _, isBool := v.(bool); isBool {
+					return v, nil
+				}
+				return number(t.text, v)
+			}
+		}
+		return nil, fmt.Errorf("unknown variable %q", t.text)
+	}
+	if t.text == "(" {
+		v, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); ok == false {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// parseCall parses the arguments of a function after the opening
+// parenthesis and calls it
+func (p *calcParser) parseCall(name string) (interface{}, error) {
+	fn, ok := calcFuncs[name]
+	if ok == false {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+	args := []interface{}{}
+	if _, ok := p.accept(")"); ok == false {
+		for {
+			v, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if v, err = number(name, v); err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if _, ok := p.accept(")"); ok {
+				break
+			}
+			return nil, fmt.Errorf("missing closing parenthesis in call to %s", name)
+		}
+	}
+	r, err := fn(args)
+	if err != nil {
+		return nil, err
+	}
+	return p.policy.Result(r)
+}
+
+// arithmetic applies a binary operator using the parser's policy
+func (p *calcParser) arithmetic(op string, left, right interface{}) (interface{}, error) {
+	a, err := number(op, left)
+	if err != nil {
+		return nil, err
+	}
+	b, err := number(op, right)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "+":
+		return p.policy.Add(a, b)
+	case "-":
+		return p.policy.Subtract(a, b)
+	case "*":
+		return p.policy.Multiply(a, b)
+	case "^":
+		return p.policy.Pow(a, b)
+	case "%":
+		return p.policy.Modulo(a, b)
+	}
+	return p.policy.Divide(a, b)
+}
@@ -0,0 +1,85 @@
+package numbers
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCalc(t *testing.T) {
+	vars := map[string]interface{}{
+		"price":    json.Number("2.5"),
+		"qty":      4,
+		"shipping": float64(3),
+		"item":     map[string]interface{}{"weight": "1,200"},
+		"member":   true,
+	}
+	testSet := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"price * qty + shipping / 2", 11.5},
+		{"-2^2", -4},
+		{"2^-1", 0.5},
+		{"2^3^2", 512},
+		{"7 % 3", 1},
+		{"-(3 - 5)", 2},
+		{"1e3 / 4", float64(250)},
+		{"item.weight / 100", int64(12)},
+		{"qty >= 4 && price < 3", true},
+		{"!member || qty == 5", false},
+		{"member == true", true},
+		{"round(10 / 3.0, 2)", 3.33},
+		{"max(qty, price, 1) + min(2, 3)", float64(6)},
+		{"clamp(sqrt(16), 0, 3)", float64(3)},
+		{"abs(floor(-2.5)) + ceil(0.1)", float64(4)},
+	}
+	for _, test := range testSet {
+		r, err := Calc(test.expr, MapLookup(vars))
+		if err != nil {
+			t.Errorf("%q, unexpected error %s", test.expr, err)
+		} else if r != test.expected {
+			t.Errorf("%q, expected %T %v, got %T %v", test.expr, test.expected, test.expected, r, r)
+		}
+	}
+}
+
+func TestCalcErrors(t *testing.T) {
+	testSet := []string{
+		"",
+		"1 +",
+		"(1 + 2",
+		"1 + 2)",
+		"qty / 0",
+		"5 % (2 - 2)",
+		"missing + 1",
+		"member + 1",
+		"1 && true",
+		"round(1, 2, 3)",
+		"nope(1)",
+		"1 $ 2",
+		"1 2",
+		"9223372036854775807 + 1",
+		"sqrt(-1)",
+		"1e308 * 10",
+		strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100),
+		strings.Repeat("-", 100) + "1",
+	}
+	for _, expr := range testSet {
+		if r, err := Calc(expr, MapLookup(map[string]interface{}{"qty": 1, "member": false})); err == nil {
+			t.Errorf("%q, expected an error, got %v", expr, r)
+		}
+	}
+	if r, err := Calc("2 * pi", nil); err == nil {
+		t.Errorf("expected an error without variables, got %v", r)
+	}
+	if r, err := (Policy{Overflow: OverflowFloat}).Calc("9223372036854775807 + 1", nil); err != nil || r != float64(1<<63) {
+		t.Errorf("expected %g, got %v, %v", float64(1<<63), r, err)
+	}
+	if r, err := Calc("sqrt(2) ^ 2", nil); err != nil || math.Abs(Float64(r)-2) > 1e-9 {
+		t.Errorf("expected 2, got %v, %v", r, err)
+	}
+}
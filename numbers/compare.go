@@ -0,0 +1,86 @@
+package numbers
+
+import (
+	"math"
+)
+
+// Compare returns -1, 0 or 1 as v1 is less than, equal to or greater
+// than v2 after promoting them to a common type like Add does, so
+// Compare(json.Number("2.5"), 2) is 1. Values that aren't numbers are
+// treated as zero.
+func Compare(v1, v2 interface{}) int {
+	a, b, nType := normalizeNumbers(v1, v2)
+	var lt, gt bool
+	switch nType {
+	case intType:
+		lt, gt = a.(int) < b.(int), a.(int) > b.(int)
+	case int64Type:
+		lt, gt = a.(int64) < b.(int64), a.(int64) > b.(int64)
+	case uint64Type:
+		lt, gt = a.(uint64) < b.(uint64), a.(uint64) > b.(uint64)
+	case float32Type:
+		lt, gt = a.(float32) < b.(float32), a.(float32) > b.(float32)
+	case float64Type:
+		lt, gt = a.(float64) < b.(float64), a.(float64) > b.(float64)
+	}
+	switch {
+	case lt:
+		return -1
+	case gt:
+		return 1
+	}
+	return 0
+}
+
+// order compares v1 and v2, ok is false if either isn't a number (see
+// IsNumeric) or is NaN
+func order(v1, v2 interface{}) (int, bool) {
+	if IsNumeric(v1) == false || IsNumeric(v2) == false {
+		return 0, false
+	}
+	if math.IsNaN(Float64(v1)) || math.IsNaN(Float64(v2)) {
+		return 0, false
+	}
+	return Compare(v1, v2), true
+}
+
+// Equal returns true if v1 and v2 are the same number, e.g. 1 and 1.0.
+// Values that aren't numbers are never equal.
+func Equal(v1, v2 interface{}) bool {
+	c, ok := order(v1, v2)
+	return ok && c == 0
+}
+
+// NotEqual returns true unless v1 and v2 are the same number
+func NotEqual(v1, v2 interface{}) bool {
+	return Equal(v1, v2) == false
+}
+
+// Less returns true if v1 is less than v2
+func Less(v1, v2 interface{}) bool {
+	c, ok := order(v1, v2)
+	return ok && c < 0
+}
+
+// LessOrEqual returns true if v1 is less than or equal to v2
+func LessOrEqual(v1, v2 interface{}) bool {
+	c, ok := order(v1, v2)
+	return ok && c <= 0
+}
+
+// Greater returns true if v1 is greater than v2
+func Greater(v1, v2 interface{}) bool {
+	c, ok := order(v1, v2)
+	return ok && c > 0
+}
+
+// GreaterOrEqual returns true if v1 is greater than or equal to v2
+func GreaterOrEqual(v1, v2 interface{}) bool {
+	c, ok := order(v1, v2)
+	return ok && c >= 0
+}
+
+// Between returns true if low <= v <= high
+func Between(v, low, high interface{}) bool {
+	return GreaterOrEqual(v, low) && LessOrEqual(v, high)
+}
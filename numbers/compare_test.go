@@ -0,0 +1,49 @@
+package numbers
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestComparisons(t *testing.T) {
+	testSet := []struct {
+		a, b               interface{}
+		eq, lt, le, gt, ge bool
+	}{
+		{float64(10), 10, true, false, true, false, true},
+		{json.Number("2.5"), 2, false, false, false, true, true},
+		{int8(-1), uint64(math.MaxUint64), false, true, true, false, false},
+		{"1,024", 1024.0, true, false, true, false, true},
+		{float32(0.5), "0.75", false, true, true, false, false},
+		{"n/a", 0, false, false, false, false, false},
+		{math.NaN(), 1, false, false, false, false, false},
+		{nil, 0, false, false, false, false, false},
+	}
+	for i, test := range testSet {
+		if r := Equal(test.a, test.b); r != test.eq {
+			t.Errorf("(%d) Equal(%v, %v) expected %t", i, test.a, test.b, test.eq)
+		}
+		if r := NotEqual(test.a, test.b); r == test.eq {
+			t.Errorf("(%d) NotEqual(%v, %v) expected %t", i, test.a, test.b, !test.eq)
+		}
+		if r := Less(test.a, test.b); r != test.lt {
+			t.Errorf("(%d) Less(%v, %v) expected %t", i, test.a, test.b, test.lt)
+		}
+		if r := LessOrEqual(test.a, test.b); r != test.le {
+			t.Errorf("(%d) LessOrEqual(%v, %v) expected %t", i, test.a, test.b, test.le)
+		}
+		if r := Greater(test.a, test.b); r != test.gt {
+			t.Errorf("(%d) Greater(%v, %v) expected %t", i, test.a, test.b, test.gt)
+		}
+		if r := GreaterOrEqual(test.a, test.b); r != test.ge {
+			t.Errorf("(%d) GreaterOrEqual(%v, %v) expected %t", i, test.a, test.b, test.ge)
+		}
+	}
+	if Between(json.Number("5"), 1, 5.0) == false || Between(6, 1, 5) || Between("x", 1, 5) {
+		t.Errorf("Between returned unexpected results")
+	}
+	if r := Compare(uint(3), int64(-3)); r != 1 {
+		t.Errorf("expected 1, got %d", r)
+	}
+}
@@ -0,0 +1,473 @@
+package numbers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode selects how a decimal is rounded to a scale
+type RoundingMode int
+
+const (
+	// HalfUp rounds to nearest, ties away from zero (2.5 -> 3, -2.5 -> -3)
+	HalfUp RoundingMode = iota
+	// HalfEven rounds to nearest, ties to the even digit, also called
+	// banker's rounding (2.5 -> 2, 3.5 -> 4)
+	HalfEven
+	// HalfDown rounds to nearest, ties toward zero (2.5 -> 2)
+	HalfDown
+	// Down rounds toward zero (truncation)
+	Down
+	// Up rounds away from zero
+	Up
+	// Floor rounds toward negative infinity
+	Floor
+	// Ceiling rounds toward positive infinity
+	Ceiling
+)
+
+// ParseRoundingMode returns the RoundingMode named by s, "half_up",
+// "half_even" (or "bankers"), "half_down", "down" (or "truncate"), "up",
+// "floor" and "ceiling" (or "ceil").
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch strings.ToLower(strings.Replace(strings.TrimSpace(s), "-", "_", -1)) {
+	case "", "half_up":
+		return HalfUp, nil
+	case "half_even", "bankers", "banker's":
+		return HalfEven, nil
+	case "half_down":
+		return HalfDown, nil
+	case "down", "truncate":
+		return Down, nil
+	case "up":
+		return Up, nil
+	case "floor":
+		return Floor, nil
+	case "ceiling", "ceil":
+		return Ceiling, nil
+	}
+	return HalfUp, fmt.Errorf("unknown rounding mode %q", s)
+}
+
+// Decimal is an exact base ten number, an arbitrary precision integer
+// scaled by a power of ten. The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// DecimalContext holds the scale and rounding used for division
+type DecimalContext struct {
+	// Scale is the maximum number of digits after the decimal point
+	// of a quotient that doesn't terminate sooner
+	Scale int
+	// Rounding is applied when a quotient is cut to Scale digits
+	Rounding RoundingMode
+}
+
+// MaxDecimalExponent is the largest exponent a parsed decimal and the
+// largest scale a decimal is rounded or divided to, larger values would
+// need huge amounts of memory (e.g. "1e1000000000")
+const MaxDecimalExponent = 10000
+
+// DefaultDecimalContext is used by DDivide and the ddiv template function
+var DefaultDecimalContext = DecimalContext{Scale: 16, Rounding: HalfEven}
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(1234, 2) is 12.34
+func NewDecimal(unscaled int64, scale int) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}.normalize()
+}
+
+// ParseDecimal converts v to a Decimal. Strings and json.Number values
+// are read exactly, floats are read from their shortest decimal form so
+// float64(0.1) is 0.1.
+func ParseDecimal(v interface{}) (Decimal, error) {
+	switch x := v.(type) {
+	case Decimal:
+		return x.normalize(), nil
+	case json.Number:
+		return parseDecimalString(x.String())
+	case string:
+		return parseDecimalString(x)
+	case int:
+		return Decimal{unscaled: big.NewInt(int64(x))}, nil
+	case int64:
+		return Decimal{unscaled: big.NewInt(x)}, nil
+	case float32:
+		return parseDecimalFloat(float64(x), 32)
+	case float64:
+		return parseDecimalFloat(x, 64)
+	case *big.Int:
+		if x == nil {
+			break
+		}
+		return Decimal{unscaled: new(big.Int).Set(x)}, nil
+	default:
+		// Other integer and float kinds
+		a, nType := canonical(v)
+		switch nType {
+		case intType, int64Type, float32Type, float64Type:
+			return ParseDecimal(a)
+		case uint64Type:
+			return Decimal{unscaled: new(big.Int).SetUint64(a.(uint64))}, nil
+		}
+	}
+	return Decimal{}, fmt.Errorf("can't convert %T to a decimal", v)
+}
+
+func parseDecimalFloat(f float64, bitSize int) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("can't convert %g to a decimal", f)
+	}
+	return parseDecimalString(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+// parseDecimalString reads [+-]digits[.digits][e[+-]digits], digits
+// may be grouped with thousands separators
+func parseDecimalString(src string) (Decimal, error) {
+	s, ok := cleanNumber(src)
+	if ok == false {
+		return Decimal{}, fmt.Errorf("can't parse decimal %q", src)
+	}
+	mantissa, exponent := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > MaxDecimalExponent || e < -MaxDecimalExponent {
+			return Decimal{}, fmt.Errorf("can't parse decimal %q, exponent out of range", src)
+		}
+		mantissa, exponent = s[0:i], e
+	}
+	scale := 0
+	if i := strings.Index(mantissa, "."); i >= 0 {
+		scale = len(mantissa) - i - 1
+		mantissa = mantissa[0:i] + mantissa[i+1:]
+	}
+	if scale-exponent < -MaxDecimalExponent {
+		return Decimal{}, fmt.Errorf("can't parse decimal %q, exponent out of range", src)
+	}
+	mantissa = strings.TrimPrefix(mantissa, "+")
+	digits := strings.TrimPrefix(mantissa, "-")
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("can't parse decimal %q", src)
+	}
+	unscaled, _ := new(big.Int).SetString(mantissa, 10)
+	return Decimal{unscaled: unscaled, scale: scale - exponent}.normalize(), nil
+}
+
+// normalize replaces a nil value with zero and a negative scale with zero
+func (d Decimal) normalize() Decimal {
+	if d.unscaled == nil {
+		d.unscaled = new(big.Int)
+	}
+	if d.scale < 0 {
+		d.unscaled = new(big.Int).Mul(d.unscaled, pow10(-d.scale))
+		d.scale = 0
+	}
+	return d
+}
+
+// pow10 returns 10^n as a big.Int
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// rescale returns the unscaled value of d at a larger scale
+func (d Decimal) rescale(scale int) *big.Int {
+	d = d.normalize()
+	if scale <= d.scale {
+		return new(big.Int).Set(d.unscaled)
+	}
+	return new(big.Int).Mul(d.unscaled, pow10(scale-d.scale))
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int {
+	return d.normalize().scale
+}
+
+// Sign returns -1, 0 or 1
+func (d Decimal) Sign() int {
+	return d.normalize().unscaled.Sign()
+}
+
+// String returns d in decimal notation, e.g. "-12.340"
+func (d Decimal) String() string {
+	d = d.normalize()
+	digits := new(big.Int).Abs(d.unscaled).String()
+	sign := ""
+	if d.unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+	i := len(digits) - d.scale
+	return sign + digits[0:i] + "." + digits[i:]
+}
+
+// Number returns d as a json.Number
+func (d Decimal) Number() json.Number {
+	return json.Number(d.String())
+}
+
+// Float64 returns the nearest float64 to d
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Add returns d + o, the scale is the larger of the two
+func (d Decimal) Add(o Decimal) Decimal {
+	scale := maxScale(d, o)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - o, the scale is the larger of the two
+func (d Decimal) Sub(o Decimal) Decimal {
+	scale := maxScale(d, o)
+	return Decimal{unscaled: new(big.Int).Sub(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+// Mul returns d * o, the scale is the sum of the two
+func (d Decimal) Mul(o Decimal) Decimal {
+	d, o = d.normalize(), o.normalize()
+	return Decimal{unscaled: new(big.Int).Mul(d.unscaled, o.unscaled), scale: d.scale + o.scale}
+}
+
+// Cmp returns -1, 0 or 1 as d is less than, equal to or greater than o
+func (d Decimal) Cmp(o Decimal) int {
+	scale := maxScale(d, o)
+	return d.rescale(scale).Cmp(o.rescale(scale))
+}
+
+// Div returns d / o. A quotient that terminates within scale digits is
+// exact, otherwise it is rounded to scale digits using mode. Trailing
+// zeros are removed.
+func (d Decimal) Div(o Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	d, o = d.normalize(), o.normalize()
+	if o.unscaled.Sign() == 0 {
+		return Decimal{}, fmt.Errorf("division by zero")
+	}
+	scale = clampScale(scale)
+	if scale < 0 {
+		scale = 0
+	}
+	// d.unscaled * 10^(scale + o.scale - d.scale) / o.unscaled has scale digits
+	num := new(big.Int).Set(d.unscaled)
+	den := new(big.Int).Set(o.unscaled)
+	if shift := scale + o.scale - d.scale; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	q := divRound(num, den, mode)
+	return Decimal{unscaled: q, scale: scale}.trim(), nil
+}
+
+// Round returns d rounded to scale digits after the decimal point using
+// mode. The result always has scale digits, e.g. 2.5 rounded to 2 is
+// 2.50. The scale is limited to ±MaxDecimalExponent.
+func (d Decimal) Round(scale int, mode RoundingMode) Decimal {
+	d = d.normalize()
+	scale = clampScale(scale)
+	if scale < 0 {
+		// Round to tens, hundreds, ...
+		q := divRound(d.unscaled, pow10(d.scale-scale), mode)
+		return Decimal{unscaled: q.Mul(q, pow10(-scale))}
+	}
+	if scale >= d.scale {
+		return Decimal{unscaled: d.rescale(scale), scale: scale}
+	}
+	return Decimal{unscaled: divRound(d.unscaled, pow10(d.scale-scale), mode), scale: scale}
+}
+
+// clampScale limits scale to ±MaxDecimalExponent
+func clampScale(scale int) int {
+	switch {
+	case scale > MaxDecimalExponent:
+		return MaxDecimalExponent
+	case scale < -MaxDecimalExponent:
+		return -MaxDecimalExponent
+	}
+	return scale
+}
+
+// checkScale returns an error naming op if scale is beyond ±MaxDecimalExponent
+func checkScale(op string, scale int) error {
+	if scale != clampScale(scale) {
+		return fmt.Errorf("%s: scale %d out of range", op, scale)
+	}
+	return nil
+}
+
+// trim removes trailing zeros after the decimal point
+func (d Decimal) trim() Decimal {
+	ten := big.NewInt(10)
+	q, r := new(big.Int), new(big.Int)
+	for d.scale > 0 {
+		q.QuoRem(d.unscaled, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		d.unscaled = new(big.Int).Set(q)
+		d.scale--
+	}
+	return d
+}
+
+// divRound returns n / d rounded to an integer using mode
+func divRound(n, d *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	// sign of the exact quotient
+	negative := (n.Sign() < 0) != (d.Sign() < 0)
+	// compare twice the remainder to the divisor to find ties
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	half := twice.Cmp(new(big.Int).Abs(d))
+	away := false
+	switch mode {
+	case HalfUp:
+		away = half >= 0
+	case HalfDown:
+		away = half > 0
+	case HalfEven:
+		away = half > 0 || (half == 0 && q.Bit(0) == 1)
+	case Up:
+		away = true
+	case Down:
+		away = false
+	case Floor:
+		away = negative
+	case Ceiling:
+		away = negative == false
+	}
+	if away {
+		if negative {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+func maxScale(a, b Decimal) int {
+	a, b = a.normalize(), b.normalize()
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// decimalPair converts two values, ok is false if either isn't a number
+func decimalPair(v1, v2 interface{}) (Decimal, Decimal, bool) {
+	a, err := ParseDecimal(v1)
+	if err != nil {
+		return a, a, false
+	}
+	b, err := ParseDecimal(v2)
+	if err != nil {
+		return a, b, false
+	}
+	return a, b, true
+}
+
+// ToDecimal returns v as an exact decimal json.Number or zero if v
+// isn't a number
+func ToDecimal(v interface{}) json.Number {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return json.Number("0")
+	}
+	return d.Number()
+}
+
+// DAdd adds v1 and v2 exactly, returning a json.Number or zero if a
+// value isn't a number. The D functions never pass through float64 so
+// DAdd("0.1", "0.2") is exactly 0.3.
+func DAdd(v1, v2 interface{}) json.Number {
+	a, b, ok := decimalPair(v1, v2)
+	if ok == false {
+		return json.Number("0")
+	}
+	return a.Add(b).Number()
+}
+
+// DSubtract subtracts v2 from v1 exactly, returning a json.Number or
+// zero if a value isn't a number
+func DSubtract(v1, v2 interface{}) json.Number {
+	a, b, ok := decimalPair(v1, v2)
+	if ok == false {
+		return json.Number("0")
+	}
+	return a.Sub(b).Number()
+}
+
+// DMultiply multiplies v1 by v2 exactly, returning a json.Number or
+// zero if a value isn't a number
+func DMultiply(v1, v2 interface{}) json.Number {
+	a, b, ok := decimalPair(v1, v2)
+	if ok == false {
+		return json.Number("0")
+	}
+	return a.Mul(b).Number()
+}
+
+// DDivide divides v1 by v2 using DefaultDecimalContext, see DecimalContext.Divide
+func DDivide(v1, v2 interface{}) json.Number {
+	return DefaultDecimalContext.Divide(v1, v2)
+}
+
+// Divide divides v1 by v2 rounding to the context's Scale, returning a
+// json.Number or zero if a value isn't a number or v2 is zero
+func (ctx DecimalContext) Divide(v1, v2 interface{}) json.Number {
+	a, b, ok := decimalPair(v1, v2)
+	if ok == false {
+		return json.Number("0")
+	}
+	q, err := a.Div(b, ctx.Scale, ctx.Rounding)
+	if err != nil {
+		return json.Number("0")
+	}
+	return q.Number()
+}
+
+// DRound rounds v to scale digits after the decimal point, mode is
+// optional and defaults to half_up (see ParseRoundingMode)
+func DRound(v interface{}, scale int, mode ...string) json.Number {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return json.Number("0")
+	}
+	m := HalfUp
+	if len(mode) > 0 {
+		if m, err = ParseRoundingMode(mode[0]); err != nil {
+			return json.Number("0")
+		}
+	}
+	return d.Round(scale, m).Number()
+}
+
+// DCompare compares v1 and v2 exactly returning -1, 0 or 1, values
+// that aren't numbers compare as zero
+func DCompare(v1, v2 interface{}) int {
+	a, err := ParseDecimal(v1)
+	if err != nil {
+		a = Decimal{}
+	}
+	b, err := ParseDecimal(v2)
+	if err != nil {
+		b = Decimal{}
+	}
+	return a.Cmp(b)
+}
@@ -0,0 +1,95 @@
+package numbers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecimalArithmetic(t *testing.T) {
+	testSet := []struct {
+		result   json.Number
+		expected string
+	}{
+		{DAdd(json.Number("0.1"), json.Number("0.2")), "0.3"},
+		{DAdd(0.1, 0.2), "0.3"},
+		{DAdd("1.10", "2.2"), "3.30"},
+		{DSubtract("10", "0.01"), "9.99"},
+		{DMultiply("19.99", 3), "59.97"},
+		{DMultiply("1.5e3", "2"), "3000"},
+		{DDivide("1", "4"), "0.25"},
+		{DDivide("2", "3"), "0.6666666666666667"},
+		{DDivide("1", "0"), "0"},
+		{DAdd("abc", 1), "0"},
+		{DRound("2.675", 2), "2.68"},
+		{DRound(2.675, 2), "2.68"},
+		{DRound("2.5", 0, "half_even"), "2"},
+		{DRound("3.5", 0, "bankers"), "4"},
+		{DRound("-2.5", 0), "-3"},
+		{DRound("-2.5", 0, "ceiling"), "-2"},
+		{DRound("-2.5", 0, "floor"), "-3"},
+		{DRound("2.1", 3), "2.100"},
+		{DRound("1250", -2, "half_even"), "1200"},
+	}
+	for i, test := range testSet {
+		if string(test.result) != test.expected {
+			t.Errorf("%d, expected %s, got %s", i, test.expected, test.result)
+		}
+	}
+
+	ctx := DecimalContext{Scale: 2, Rounding: Down}
+	if r := ctx.Divide("10", "3"); r != "3.33" {
+		t.Errorf("expected 3.33, got %s", r)
+	}
+	if r := DCompare("1.10", 1.1); r != 0 {
+		t.Errorf("expected 1.10 and 1.1 to be equal, got %d", r)
+	}
+	if _, err := ParseRoundingMode("sideways"); err == nil {
+		t.Errorf("expected an error for an unknown rounding mode")
+	}
+}
+
+func TestDecimalLimits(t *testing.T) {
+	for _, src := range []string{"1e1000000000", "1e-1000000000", "1e10001", "0.5e99999999999999999999"} {
+		if d, err := ParseDecimal(src); err == nil {
+			t.Errorf("%q, expected an error, got %s", src, d)
+		}
+	}
+	if d, err := ParseDecimal("1e10000"); err != nil || d.exponent() != 10000 {
+		t.Errorf("expected 1e10000, got %v", err)
+	}
+	if _, err := ParseNumber("1e1000000000"); err == nil {
+		t.Errorf("expected an error for 1e1000000000")
+	}
+	if r := DRound("1.5", 1000000000); len(r) != MaxDecimalExponent+2 {
+		t.Errorf("expected the scale to be limited, got %d digits", len(r))
+	}
+	if r := DRound("1234.5", -1000000000); r != "0" {
+		t.Errorf("expected 0, got %s", r)
+	}
+	if _, err := DRoundErr("1.5", 1000000000); err == nil {
+		t.Errorf("expected an error for a huge scale")
+	}
+	if _, err := RoundErr(1.5, -1000000000, HalfUp); err == nil {
+		t.Errorf("expected an error for a huge precision")
+	}
+}
+
+func TestDCompare(t *testing.T) {
+	testSet := []struct {
+		v1, v2   interface{}
+		expected int
+	}{
+		{"1.10", "1.1", 0},
+		{"2", 10, -1},
+		{"x", 5, -1},
+		{5, "x", 1},
+		{"x", -5, 1},
+		{"x", "y", 0},
+		{"x", 0, 0},
+	}
+	for _, test := range testSet {
+		if r := DCompare(test.v1, test.v2); r != test.expected {
+			t.Errorf("%v, %v, expected %d, got %d", test.v1, test.v2, test.expected, r)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package numbers
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/internal/lang"
+)
+
+// CompactUnit is a power of ten and the suffix used for it in compact
+// notation, e.g. {1e6, "M"} renders 1200000 as 1.2M
+type CompactUnit struct {
+	Value  float64
+	Suffix string
+}
+
+// NumberLocale holds the separators and patterns used to format
+// numbers in a language
+type NumberLocale struct {
+	// Decimal separates the integer and fraction digits
+	Decimal string
+	// Group separates each group of three integer digits
+	Group string
+	// MinGroupDigits is the smallest number of integer digits that are
+	// grouped, e.g. Spanish writes 1234 but 12.345. Zero always groups.
+	MinGroupDigits int
+	// Percent is the percent pattern, "#" is replaced by the number
+	Percent string
+	// Currency is the currency pattern, "#" is replaced by the number
+	// and "¤" by the currency symbol
+	Currency string
+	// Compact lists the compact notation units from smallest to largest
+	Compact []CompactUnit
+}
+
+var (
+	// NumberLocales holds the decimal and group separators, percent and
+	// currency patterns and compact units used by FormatNumber and
+	// friends, keyed by lower case language code
+	NumberLocales = map[string]*NumberLocale{
+		"en": {
+			Decimal:  ".",
+			Group:    ",",
+			Percent:  "#%",
+			Currency: "¤#",
+			Compact:  []CompactUnit{{1e3, "K"}, {1e6, "M"}, {1e9, "B"}, {1e12, "T"}},
+		},
+		"es": {
+			Decimal:        ",",
+			Group:          ".",
+			MinGroupDigits: 5,
+			Percent:        "#\u00a0%",
+			Currency:       "#\u00a0¤",
+			Compact:        []CompactUnit{{1e3, "\u00a0mil"}, {1e6, "\u00a0M"}, {1e9, "\u00a0mil\u00a0M"}, {1e12, "\u00a0B"}},
+		},
+		"fr": {
+			Decimal:  ",",
+			Group:    "\u202f",
+			Percent:  "#\u202f%",
+			Currency: "#\u00a0¤",
+			Compact:  []CompactUnit{{1e3, "\u00a0k"}, {1e6, "\u00a0M"}, {1e9, "\u00a0Md"}, {1e12, "\u00a0Bn"}},
+		},
+		"de": {
+			Decimal:  ",",
+			Group:    ".",
+			Percent:  "#\u00a0%",
+			Currency: "#\u00a0¤",
+			Compact:  []CompactUnit{{1e3, "\u00a0Tsd."}, {1e6, "\u00a0Mio."}, {1e9, "\u00a0Mrd."}, {1e12, "\u00a0Bio."}},
+		},
+		"zh": {
+			Decimal:  ".",
+			Group:    ",",
+			Percent:  "#%",
+			Currency: "¤#",
+			Compact:  []CompactUnit{{1e4, "万"}, {1e8, "亿"}, {1e12, "万亿"}},
+		},
+	}
+
+	// CurrencySymbols maps ISO 4217 codes to symbols, codes not listed
+	// are shown as given (so a symbol like "$" can be passed directly)
+	CurrencySymbols = map[string]string{
+		"AUD": "A$",
+		"CAD": "CA$",
+		"CHF": "CHF",
+		"CNY": "¥",
+		"EUR": "€",
+		"GBP": "£",
+		"INR": "₹",
+		"JPY": "¥",
+		"KRW": "₩",
+		"MXN": "MX$",
+		"USD": "$",
+	}
+
+	// CurrencyDecimals holds the number of minor unit digits for
+	// currencies that don't use two
+	CurrencyDecimals = map[string]int{
+		"JPY": 0,
+		"KRW": 0,
+	}
+)
+
+// LookupNumberLocale returns the NumberLocale for a language code.
+// Region and encoding suffixes are ignored if there is no exact match,
+// so "de-CH" and "de_DE.UTF-8" both find "de".
+func LookupNumberLocale(name string) (*NumberLocale, bool) {
+	for _, tag := range lang.Tags(name) {
+		if l, ok := NumberLocales[tag]; ok {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// numberLocale returns l or English if l is nil
+func numberLocale(l *NumberLocale) *NumberLocale {
+	if l == nil {
+		return NumberLocales["en"]
+	}
+	return l
+}
+
+// render returns the digits of d (ignoring its sign) with l's
+// separators and whether d is negative
+func render(d Decimal, l *NumberLocale) (string, bool) {
+	s := d.String()
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	intPart, fracPart := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		intPart, fracPart = s[0:i], s[i+1:]
+	}
+	if len(intPart) > 3 && len(intPart) >= l.MinGroupDigits {
+		var groups []string
+		for len(intPart) > 3 {
+			groups = append([]string{intPart[len(intPart)-3:]}, groups...)
+			intPart = intPart[0 : len(intPart)-3]
+		}
+		intPart = strings.Join(append([]string{intPart}, groups...), l.Group)
+	}
+	if fracPart != "" {
+		return intPart + l.Decimal + fracPart, negative
+	}
+	return intPart, negative
+}
+
+// withSign prefixes s with a minus sign when negative
+func withSign(s string, negative bool) string {
+	if negative {
+		return "-" + s
+	}
+	return s
+}
+
+// roundTo rounds d to decimals places, a negative decimals leaves d as is
+func roundTo(d Decimal, decimals int) Decimal {
+	if decimals < 0 {
+		return d
+	}
+	return d.Round(decimals, HalfEven)
+}
+
+// FormatNumber formats v with l's grouping and decimal separators,
+// rounding (half even) to decimals places. A negative decimals keeps
+// every digit of v. Values that aren't numbers are returned as is.
+func FormatNumber(v interface{}, decimals int, l *NumberLocale) string {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return withSign(render(roundTo(d, decimals), numberLocale(l)))
+}
+
+// FormatSignificant formats v rounded to digits significant digits,
+// e.g. 1234.5 to 3 digits is "1,230" and 0.012345 is "0.0123"
+func FormatSignificant(v interface{}, digits int, l *NumberLocale) string {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	if digits < 1 {
+		digits = 1
+	}
+	return withSign(render(roundSignificant(d, digits), numberLocale(l)))
+}
+
+// exponent returns the power of ten of d's leading digit, e.g. 2 for
+// 123.4 and -2 for 0.012
+func (d Decimal) exponent() int {
+	d = d.normalize()
+	return len(new(big.Int).Abs(d.unscaled).String()) - d.scale - 1
+}
+
+// roundSignificant rounds d to digits significant digits
+func roundSignificant(d Decimal, digits int) Decimal {
+	if d.Sign() == 0 {
+		return d.normalize()
+	}
+	return d.Round(digits-1-d.exponent(), HalfEven)
+}
+
+// negate returns -d
+func negate(d Decimal) Decimal {
+	return Decimal{}.Sub(d)
+}
+
+// compact divides d by the largest unit of l it reaches and rounds the
+// result to decimals places. A negative decimals keeps two significant
+// digits but every integer digit, so 1234567 is 1.2M and 123456789 is
+// 123M. It returns the rounded value and the unit's suffix.
+func compact(d Decimal, decimals int, l *NumberLocale) (Decimal, string) {
+	abs := d
+	if d.Sign() < 0 {
+		abs = negate(d)
+	}
+	i := len(l.Compact) - 1
+	for i >= 0 {
+		if unit, _ := ParseDecimal(l.Compact[i].Value); abs.Cmp(unit) >= 0 {
+			break
+		}
+		i--
+	}
+	unit, suffix := NewDecimal(1, 0), ""
+	if i >= 0 {
+		unit, _ = ParseDecimal(l.Compact[i].Value)
+		suffix = l.Compact[i].Suffix
+	}
+	q, _ := d.Div(unit, 16, HalfEven)
+	switch {
+	case decimals >= 0:
+		q = q.Round(decimals, HalfEven)
+	case q.Sign() != 0 && q.exponent() >= 1:
+		q = q.Round(0, HalfEven)
+	default:
+		q = roundSignificant(q, 2).trim()
+	}
+	// Rounding up can reach the next unit, e.g. 999999 is 1M not 1000K
+	if i+1 < len(l.Compact) {
+		next, _ := ParseDecimal(l.Compact[i+1].Value)
+		ratio, _ := next.Div(unit, 0, HalfEven)
+		if q.Cmp(ratio) >= 0 || negate(q).Cmp(ratio) >= 0 {
+			if d.Sign() < 0 {
+				next = negate(next)
+			}
+			return compact(next, decimals, l)
+		}
+	}
+	return q, suffix
+}
+
+// FormatCompact formats v in compact notation, e.g. 1234567 is "1.2M"
+// in English and "1,2 Mio." in German. A negative decimals keeps two
+// significant digits.
+func FormatCompact(v interface{}, decimals int, l *NumberLocale) string {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	l = numberLocale(l)
+	q, suffix := compact(d, decimals, l)
+	s, negative := render(q, l)
+	return withSign(s+suffix, negative)
+}
+
+// FormatPercent formats the fraction v as a percentage, e.g. 0.125 is
+// "12.5%". A negative decimals keeps every digit.
+func FormatPercent(v interface{}, decimals int, l *NumberLocale) string {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	l = numberLocale(l)
+	s, negative := render(roundTo(d.Mul(NewDecimal(100, 0)).trim(), decimals), l)
+	return withSign(strings.Replace(l.Percent, "#", s, 1), negative)
+}
+
+// currencyPattern fills in l's currency pattern, a space is added
+// after a symbol ending in a letter when it comes before the number
+// (e.g. CHF 12.00)
+func currencyPattern(s string, currency string, l *NumberLocale) string {
+	symbol := currency
+	if sym, ok := CurrencySymbols[strings.ToUpper(currency)]; ok {
+		symbol = sym
+	}
+	pattern := l.Currency
+	if strings.HasPrefix(pattern, "¤#") {
+		if r, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(r) {
+			pattern = strings.Replace(pattern, "¤#", "¤\u00a0#", 1)
+		}
+	}
+	return strings.Replace(strings.Replace(pattern, "#", s, 1), "¤", symbol, 1)
+}
+
+// currencyDecimals returns decimals or, if negative, the minor units of currency
+func currencyDecimals(currency string, decimals int) int {
+	if decimals >= 0 {
+		return decimals
+	}
+	if n, ok := CurrencyDecimals[strings.ToUpper(currency)]; ok {
+		return n
+	}
+	return 2
+}
+
+// FormatCurrency formats v as an amount of currency, an ISO 4217 code
+// (e.g. "USD") or a symbol. A negative decimals uses the currency's
+// minor units (2 for most, 0 for JPY).
+func FormatCurrency(v interface{}, currency string, decimals int, l *NumberLocale) string {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	l = numberLocale(l)
+	s, negative := render(d.Round(currencyDecimals(currency, decimals), HalfEven), l)
+	return withSign(currencyPattern(s, currency, l), negative)
+}
+
+// FormatCurrencyCompact formats v as currency in compact notation, e.g.
+// 1234567 USD is "$1.2M"
+func FormatCurrencyCompact(v interface{}, currency string, decimals int, l *NumberLocale) string {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	l = numberLocale(l)
+	q, suffix := compact(d, decimals, l)
+	s, negative := render(q, l)
+	return withSign(currencyPattern(s+suffix, currency, l), negative)
+}
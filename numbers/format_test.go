@@ -0,0 +1,91 @@
+package numbers
+
+import (
+	"testing"
+)
+
+func TestFormatNumber(t *testing.T) {
+	en, de := NumberLocales["en"], NumberLocales["de"]
+	es, _ := LookupNumberLocale("es-MX")
+	fr, _ := LookupNumberLocale("fr_CA.UTF-8")
+	testSet := []struct {
+		value    string
+		decimals int
+		l        *NumberLocale
+		expected string
+	}{
+		{"1234567.891", 2, en, "1,234,567.89"},
+		{"1234567.891", -1, en, "1,234,567.891"},
+		{"1234567.891", 2, de, "1.234.567,89"},
+		{"1234567.891", 2, fr, "1\u202f234\u202f567,89"},
+		{"1234.5", 2, es, "1234,50"},
+		{"12345.5", 0, es, "12.346"},
+		{"-0.125", 2, en, "-0.12"},
+		{"999", 0, nil, "999"},
+	}
+	for _, test := range testSet {
+		if r := FormatNumber(test.value, test.decimals, test.l); r != test.expected {
+			t.Errorf("%s (%d), expected %q, got %q", test.value, test.decimals, test.expected, r)
+		}
+	}
+	if r := FormatNumber("n/a", 2, en); r != "n/a" {
+		t.Errorf("expected n/a, got %q", r)
+	}
+	if r := FormatSignificant(1234.5, 3, en); r != "1,230" {
+		t.Errorf("expected 1,230, got %q", r)
+	}
+	if r := FormatSignificant(0.012345, 3, en); r != "0.0123" {
+		t.Errorf("expected 0.0123, got %q", r)
+	}
+}
+
+func TestFormatCompact(t *testing.T) {
+	testSet := []struct {
+		value    interface{}
+		decimals int
+		locale   string
+		expected string
+	}{
+		{1234567, -1, "en", "1.2M"},
+		{123456789, -1, "en", "123M"},
+		{999999, -1, "en", "1M"},
+		{-1500, -1, "en", "-1.5K"},
+		{999, -1, "en", "999"},
+		{1000000, 2, "en", "1.00M"},
+		{1234567, -1, "de", "1,2\u00a0Mio."},
+		{2500000000, -1, "es", "2,5\u00a0mil\u00a0M"},
+		{123456, -1, "zh", "12万"},
+		{1234, -1, "zh", "1,234"},
+	}
+	for _, test := range testSet {
+		l, _ := LookupNumberLocale(test.locale)
+		if r := FormatCompact(test.value, test.decimals, l); r != test.expected {
+			t.Errorf("%v (%s), expected %q, got %q", test.value, test.locale, test.expected, r)
+		}
+	}
+}
+
+func TestFormatPercentAndCurrency(t *testing.T) {
+	en, de, zh := NumberLocales["en"], NumberLocales["de"], NumberLocales["zh"]
+	testSet := []struct {
+		r, expected string
+	}{
+		{FormatPercent(0.125, -1, en), "12.5%"},
+		{FormatPercent("0.125", 0, en), "12%"},
+		{FormatPercent(0.5, 1, de), "50,0\u00a0%"},
+		{FormatPercent(-0.07, -1, NumberLocales["fr"]), "-7\u202f%"},
+		{FormatCurrency(1234.5, "USD", -1, en), "$1,234.50"},
+		{FormatCurrency(-1234.5, "EUR", -1, de), "-1.234,50\u00a0€"},
+		{FormatCurrency(1234.5, "JPY", -1, en), "¥1,234"},
+		{FormatCurrency(12, "CHF", -1, en), "CHF\u00a012.00"},
+		{FormatCurrency(12, "£", 0, en), "£12"},
+		{FormatCurrency(88.8, "cny", -1, zh), "¥88.80"},
+		{FormatCurrencyCompact(1234567, "USD", -1, en), "$1.2M"},
+		{FormatCurrencyCompact(1234567, "EUR", -1, de), "1,2\u00a0Mio.\u00a0€"},
+	}
+	for i, test := range testSet {
+		if test.r != test.expected {
+			t.Errorf("(%d) expected %q, got %q", i, test.expected, test.r)
+		}
+	}
+}
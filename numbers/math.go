@@ -0,0 +1,178 @@
+package numbers
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+)
+
+// valueType returns the working type of v with json.Number resolved to
+// int64Type or float64Type
+func valueType(v interface{}) int {
+	nType := numberType(v)
+	if nType == jsonNumberType {
+		return normalizeJSONNumberType(v.(json.Number))
+	}
+	return nType
+}
+
+// Round rounds v to precision digits after the decimal point using mode.
+// A negative precision rounds to tens, hundreds, etc. Rounding is done
+// in decimal so 2.675 rounds half up to 2.68 rather than the 2.67 a
+// float64 calculation gives. Integers stay integers of the same type,
+// other values become a float32 or float64.
+func Round(v interface{}, precision int, mode RoundingMode) interface{} {
+	nType := valueType(v)
+	if nType == naNType {
+		return 0
+	}
+	if nType != float32Type && nType != float64Type && precision >= 0 {
+		return toType(v, nType)
+	}
+	d, err := ParseDecimal(toType(v, nType))
+	if err != nil {
+		// NaN and infinities can't be rounded
+		return toType(v, nType)
+	}
+	n, _ := ParseNumber(d.Round(precision, mode).String())
+	return toType(n, nType)
+}
+
+// FloorValue returns the greatest integer value less than or equal to
+// v, integers are returned unchanged
+func FloorValue(v interface{}) interface{} {
+	switch valueType(v) {
+	case float32Type:
+		return float32(math.Floor(float64(toType(v, float32Type).(float32))))
+	case float64Type:
+		return math.Floor(toType(v, float64Type).(float64))
+	case naNType:
+		return 0
+	}
+	return toType(v, valueType(v))
+}
+
+// CeilValue returns the least integer value greater than or equal to
+// v, integers are returned unchanged
+func CeilValue(v interface{}) interface{} {
+	switch valueType(v) {
+	case float32Type:
+		return float32(math.Ceil(float64(toType(v, float32Type).(float32))))
+	case float64Type:
+		return math.Ceil(toType(v, float64Type).(float64))
+	case naNType:
+		return 0
+	}
+	return toType(v, valueType(v))
+}
+
+// Abs returns the absolute value of v. The absolute value of the most
+// negative int or int64 doesn't fit in its type so it is returned as a
+// uint64.
+func Abs(v interface{}) interface{} {
+	nType := valueType(v)
+	switch a := toType(v, nType).(type) {
+	case int:
+		if a < 0 && -a < 0 {
+			return uint64(-int64(a+1)) + 1
+		} else if a < 0 {
+			return -a
+		}
+		return a
+	case int64:
+		if a < 0 && -a < 0 {
+			return uint64(-(a + 1)) + 1
+		} else if a < 0 {
+			return -a
+		}
+		return a
+	case uint64:
+		return a
+	case float32:
+		return float32(math.Abs(float64(a)))
+	case float64:
+		return math.Abs(a)
+	}
+	return 0
+}
+
+// Pow returns base raised to exp. Integer bases with a non-negative
+// integer exponent give an integer result when it fits in the promoted
+// type, otherwise the result is a float64.
+func Pow(base, exp interface{}) interface{} {
+	a, b, nType := normalizeNumbers(base, exp)
+	switch nType {
+	case intType, int64Type:
+		x, y := toType(a, int64Type).(int64), toType(b, int64Type).(int64)
+		if r, ok := bigPow(big.NewInt(x), big.NewInt(y), 64); ok && r.IsInt64() {
+			return toType(r.Int64(), nType)
+		}
+	case uint64Type:
+		if r, ok := bigPow(new(big.Int).SetUint64(a.(uint64)), new(big.Int).SetUint64(b.(uint64)), 64); ok && r.IsUint64() {
+			return r.Uint64()
+		}
+	case float32Type:
+		return float32(math.Pow(float64(a.(float32)), float64(b.(float32))))
+	}
+	return math.Pow(Float64(base), Float64(exp))
+}
+
+// bigPow returns x to the power y if y isn't negative and the result
+// needs no more than maxBits bits, so a huge exponent can't exhaust
+// memory
+func bigPow(x, y *big.Int, maxBits int) (*big.Int, bool) {
+	if y.Sign() < 0 {
+		return nil, false
+	}
+	abs := new(big.Int).Abs(x)
+	if abs.Cmp(big.NewInt(1)) > 0 && (y.IsInt64() == false || int64(abs.BitLen()-1)*y.Int64() > int64(maxBits)) {
+		return nil, false
+	}
+	if abs.Cmp(big.NewInt(1)) <= 0 && y.Cmp(big.NewInt(2)) > 0 {
+		// 0, 1 and -1 to a large power, only the parity of y matters
+		y = new(big.Int).Add(big.NewInt(2), new(big.Int).And(y, big.NewInt(1)))
+	}
+	return new(big.Int).Exp(x, y, nil), true
+}
+
+// Sqrt returns the square root of v as a float64, negative values give NaN
+func Sqrt(v interface{}) float64 {
+	return math.Sqrt(Float64(v))
+}
+
+// Min returns the smallest of its arguments after promoting them to a
+// common type like Add does, e.g. Min(3, 2.5) is 2.5 and Min(3, int64(7))
+// is int64(3)
+func Min(v1, v2 interface{}, more ...interface{}) interface{} {
+	result := v1
+	for _, v := range append([]interface{}{v2}, more...) {
+		a, b, _ := normalizeNumbers(result, v)
+		if Compare(b, a) < 0 {
+			result = b
+		} else {
+			result = a
+		}
+	}
+	return result
+}
+
+// Max returns the largest of its arguments after promoting them to a
+// common type like Add does
+func Max(v1, v2 interface{}, more ...interface{}) interface{} {
+	result := v1
+	for _, v := range append([]interface{}{v2}, more...) {
+		a, b, _ := normalizeNumbers(result, v)
+		if Compare(b, a) > 0 {
+			result = b
+		} else {
+			result = a
+		}
+	}
+	return result
+}
+
+// Clamp limits v to the range low to high (inclusive), the result is
+// promoted to the common type of all three values
+func Clamp(v, low, high interface{}) interface{} {
+	return Max(low, Min(v, high))
+}
@@ -0,0 +1,73 @@
+package numbers
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestRound(t *testing.T) {
+	testSet := []struct {
+		v         interface{}
+		precision int
+		mode      RoundingMode
+		expected  interface{}
+	}{
+		{2.675, 2, HalfUp, 2.68},
+		{2.5, 0, HalfUp, float64(3)},
+		{2.5, 0, HalfEven, float64(2)},
+		{-2.5, 0, HalfUp, float64(-3)},
+		{float32(1.25), 1, HalfEven, float32(1.2)},
+		{"3.14159", 3, HalfUp, 3.142},
+		{json.Number("7.45"), 1, HalfEven, 7.4},
+		{1234, -2, HalfUp, 1200},
+		{int64(1250), -2, HalfEven, int64(1200)},
+		{uint8(7), 2, HalfUp, 7},
+		{math.Inf(1), 2, HalfUp, math.Inf(1)},
+		{"n/a", 2, HalfUp, 0},
+	}
+	for _, test := range testSet {
+		if r := Round(test.v, test.precision, test.mode); r != test.expected {
+			t.Errorf("round %T %v to %d, expected %T %v, got %T %v", test.v, test.v, test.precision, test.expected, test.expected, r, r)
+		}
+	}
+}
+
+func TestMathOperations(t *testing.T) {
+	testSet := []struct {
+		r, expected interface{}
+	}{
+		{FloorValue(-2.5), float64(-3)},
+		{FloorValue(float32(2.5)), float32(2)},
+		{FloorValue(int8(5)), 5},
+		{CeilValue("2.1"), float64(3)},
+		{CeilValue(int64(4)), int64(4)},
+		{Abs(-3), 3},
+		{Abs(int64(math.MinInt64)), uint64(1) << 63},
+		{Abs(float32(-1.5)), float32(1.5)},
+		{Abs(json.Number("-4")), int64(4)},
+		{Pow(2, 10), 1024},
+		{Pow(int64(3), 2), int64(9)},
+		{Pow(2, 64), math.Pow(2, 64)},
+		{Pow(uint64(2), uint64(63)), uint64(1) << 63},
+		{Pow(2, -1), 0.5},
+		{Pow(4, 0.5), float64(2)},
+		{Sqrt(16), float64(4)},
+		{Min(3, 2.5), 2.5},
+		{Min(3, int64(7)), int64(3)},
+		{Min(5, 4, "1", 3), int64(1)},
+		{Max(uint(3), -1), int64(3)},
+		{Max(1, 2, 3.5), 3.5},
+		{Clamp(12, 0, 10), 10},
+		{Clamp(-2, 0, 10), 0},
+		{Clamp(0.5, 0, 1), 0.5},
+	}
+	for i, test := range testSet {
+		if test.r != test.expected {
+			t.Errorf("(%d) expected %T %v, got %T %v", i, test.expected, test.expected, test.r, test.r)
+		}
+	}
+	if r := Sqrt(-1); math.IsNaN(r) == false {
+		t.Errorf("expected NaN, got %g", r)
+	}
+}
@@ -2,14 +2,32 @@ package numbers
 
 import (
 	"encoding/json"
-	"log"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
 )
 
-// tmplfn supports calculations with four types of numbers,
-// int64, int, float64 and float32. In Add, Substract, Mutliply, Divide,
-// and Modulo the input values are normalized to the highest bit width of the two.
-// If the input value is a string or json.Number it is normalized to either float64 or int64 or
-// zero if parse fails.
+// tmplfn supports calculations with every Go integer and float kind
+// (including named types like `type Count uint32`) and json.Number. In
+// Add, Substract, Mutliply, Divide, and Modulo the input values are
+// first mapped onto five working types,
+//
+//	int8, int16, int32, int, uint8, uint16 -> int
+//	int64, uint32                          -> int64
+//	uint, uint64, uintptr                  -> uint64
+//	float32                                -> float32
+//	float64                                -> float64
+//
+// then normalized to the highest bit width of the two. If either is a
+// float both become floats. Mixing uint64 with a signed integer gives
+// int64 when the unsigned value fits in an int64 and float64 when it
+// doesn't, so large unsigned values never wrap to negative numbers.
+// If the input value is a string or json.Number it is normalized to
+// int64 for integers (uint64 for large unsigned values), float64 for
+// other numbers or zero if parse fails, so json.Number("7") divided by
+// 2 is int64 3 like 7 divided by 2. Strings may have a leading "+", thousands separators
+// (e.g. "1,024") and an exponent (e.g. "6.02e23"), see ParseNumber.
 const (
 	naNType = iota
 	intType
@@ -17,6 +35,7 @@ const (
 	float32Type
 	float64Type
 	jsonNumberType
+	uint64Type
 )
 
 // numberType returns the best guess of numeric types this package supports
@@ -32,92 +51,184 @@ func numberType(value interface{}) int {
 		return float32Type
 	case int:
 		return intType
-	default:
+	case uint64:
+		return uint64Type
+	case string:
+		if n, err := ParseNumber(value.(string)); err == nil {
+			return numberType(n)
+		}
 		return naNType
 	}
+	_, nType := canonical(value)
+	return nType
+}
+
+// canonical maps any integer or float kind onto one of the working
+// types int, int64, uint64, float32 or float64, returning the value and
+// its type. A *big.Int too large for an int64 or uint64 becomes a
+// float64. Other values return nil and naNType.
+func canonical(v interface{}) (interface{}, int) {
+	switch x := v.(type) {
+	case int:
+		return x, intType
+	case int64:
+		return x, int64Type
+	case uint64:
+		return x, uint64Type
+	case float32:
+		return x, float32Type
+	case float64:
+		return x, float64Type
+	case json.Number:
+		return nil, naNType
+	case *big.Int:
+		// Results promoted by OverflowBig
+		switch {
+		case x == nil:
+			return nil, naNType
+		case x.IsInt64():
+			return x.Int64(), int64Type
+		case x.IsUint64():
+			return x.Uint64(), uint64Type
+		}
+		f, _ := new(big.Float).SetInt(x).Float64()
+		return f, float64Type
+	}
+	if v == nil {
+		return nil, naNType
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int:
+		return int(rv.Int()), intType
+	case reflect.Int64:
+		return rv.Int(), int64Type
+	case reflect.Uint8, reflect.Uint16:
+		return int(rv.Uint()), intType
+	case reflect.Uint32:
+		return int64(rv.Uint()), int64Type
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), uint64Type
+	case reflect.Float32:
+		return float32(rv.Float()), float32Type
+	case reflect.Float64:
+		return rv.Float(), float64Type
+	}
+	return nil, naNType
 }
 
-// toType converts an interface to the targetType (must be a float64Type, float32Type, int64Type or intType)
+// toType converts an interface to the targetType (must be a float64Type, float32Type, int64Type, uint64Type or intType)
 func toType(v interface{}, targetType int) interface{} {
 	var (
 		a     interface{}
 		nType int
 	)
 
-	// normalize to either a float64, float32, int64 or int
+	// normalize to either a float64, float32, int64, uint64 or int
 	switch v.(type) {
 	case json.Number:
-		a = 0
+		a = int64(0)
 		nType = int64Type
 		if i, err := v.(json.Number).Int64(); err == nil {
 			a = i
 			nType = int64Type
+		} else if u, err := strconv.ParseUint(v.(json.Number).String(), 10, 64); err == nil {
+			a = u
+			nType = uint64Type
 		} else if f, err := v.(json.Number).Float64(); err == nil {
 			a = f
 			nType = float64Type
 		}
-	case float64:
-		a = v.(float64)
-		nType = float64Type
-	case float32:
-		a = v.(float32)
-		nType = float32Type
-	case int64:
-		a = v.(int64)
-		nType = int64Type
-	case int:
-		a = v.(int)
-		nType = intType
-	default:
-		// NOTE: If it is not a supported type then treat value as zero
+	case string:
 		a = int(0)
 		nType = intType
+		if n, err := ParseNumber(v.(string)); err == nil {
+			a, nType = canonical(n)
+		}
+	default:
+		a, nType = canonical(v)
+		if nType == naNType {
+			// NOTE: If it is not a supported type then treat value as zero
+			a = int(0)
+			nType = intType
+		}
 	}
 
 	// now convert to target type
 	switch targetType {
 	case float64Type:
-		if nType == int64Type {
+		switch nType {
+		case int64Type:
 			return float64(a.(int64))
-		} else if nType == intType {
+		case intType:
 			return float64(a.(int))
-		} else if nType == float32Type {
+		case uint64Type:
+			return float64(a.(uint64))
+		case float32Type:
 			return float64(a.(float32))
 		}
 		return a.(float64)
 	case int64Type:
-		if nType == int64Type {
+		switch nType {
+		case int64Type:
 			return a.(int64)
-		} else if nType == intType {
+		case intType:
 			return int64(a.(int))
-		} else if nType == float32Type {
+		case uint64Type:
+			return int64(a.(uint64))
+		case float32Type:
 			return int64(a.(float32))
 		}
 		return int64(a.(float64))
+	case uint64Type:
+		switch nType {
+		case int64Type:
+			return uint64(a.(int64))
+		case intType:
+			return uint64(a.(int))
+		case uint64Type:
+			return a.(uint64)
+		case float32Type:
+			return uint64(a.(float32))
+		}
+		return uint64(a.(float64))
 	case float32Type:
-		if nType == int64Type {
+		switch nType {
+		case int64Type:
 			return float32(a.(int64))
-		} else if nType == intType {
+		case intType:
 			return float32(a.(int))
-		} else if nType == float32Type {
+		case uint64Type:
+			return float32(a.(uint64))
+		case float32Type:
 			return a.(float32)
 		}
 		return float32(a.(float64))
 	default:
 		// intType is the default type
-		if nType == int64Type {
+		switch nType {
+		case int64Type:
 			return int(a.(int64))
-		} else if nType == intType {
+		case intType:
 			return a.(int)
-		} else if nType == float32Type {
+		case uint64Type:
+			return int(a.(uint64))
+		case float32Type:
 			return int(a.(float32))
 		}
 		return int(a.(float64))
 	}
 }
 
-// normalizeJSONNumber converts to either a float64 or int64 or zero
+// normalizeJSONNumberType returns int64Type for integers, uint64Type
+// for integers too large for an int64 and float64Type for the rest
 func normalizeJSONNumberType(n json.Number) int {
+	if _, err := n.Int64(); err == nil {
+		return int64Type
+	}
+	if _, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return uint64Type
+	}
 	if _, err := n.Float64(); err == nil {
 		return float64Type
 	}
@@ -149,6 +260,24 @@ func normalizeNumbers(v1, v2 interface{}) (interface{}, interface{}, int) {
 		a = toType(v1, float32Type)
 		b = toType(v2, float32Type)
 		nType = float32Type
+	case aType == uint64Type && (bType == uint64Type || bType == naNType):
+		a = toType(v1, uint64Type)
+		b = toType(v2, uint64Type)
+		nType = uint64Type
+	case bType == uint64Type && aType == naNType:
+		a = toType(v1, uint64Type)
+		b = toType(v2, uint64Type)
+		nType = uint64Type
+	case aType == uint64Type || bType == uint64Type:
+		// Mixing signed and unsigned, use int64 unless the unsigned
+		// value is too large for it
+		nType = int64Type
+		if (aType == uint64Type && toType(v1, uint64Type).(uint64) > math.MaxInt64) ||
+			(bType == uint64Type && toType(v2, uint64Type).(uint64) > math.MaxInt64) {
+			nType = float64Type
+		}
+		a = toType(v1, nType)
+		b = toType(v2, nType)
 	case aType == int64Type || bType == int64Type:
 		a = toType(v1, int64Type)
 		b = toType(v2, int64Type)
@@ -171,37 +300,26 @@ func IsNumber(v interface{}) bool {
 		if _, err := v.(json.Number).Int64(); err == nil {
 			return true
 		}
-	case float64:
-		return true
-	case float32:
-		return true
-	case int64:
-		return true
-	case int:
-		return true
+		return false
 	}
-	return false
+	_, nType := canonical(v)
+	return nType != naNType
 }
 
 // IsZero checks to see if a Number is zero
 func IsZero(v interface{}) bool {
-	switch v.(type) {
-	case int:
-		if v == int(0) {
-			return true
-		}
-	case int64:
-		if v == int64(0) {
-			return true
-		}
-	case float32:
-		if v == float32(0) {
-			return true
-		}
-	case float64:
-		if v == float64(0) {
-			return true
-		}
+	a, nType := canonical(v)
+	switch nType {
+	case intType:
+		return a.(int) == 0
+	case int64Type:
+		return a.(int64) == 0
+	case uint64Type:
+		return a.(uint64) == 0
+	case float32Type:
+		return a.(float32) == 0
+	case float64Type:
+		return a.(float64) == 0
 	}
 	return false
 }
@@ -216,6 +334,8 @@ func Add(v1, v2 interface{}) interface{} {
 		return a.(float32) + b.(float32)
 	case int64Type:
 		return a.(int64) + b.(int64)
+	case uint64Type:
+		return a.(uint64) + b.(uint64)
 	case float64Type:
 		return a.(float64) + b.(float64)
 	default:
@@ -223,6 +343,21 @@ func Add(v1, v2 interface{}) interface{} {
 	}
 }
 
+// subtractUint64 returns a - b, if b is larger than a the result is
+// an int64 or, if too large for an int64, a float64 rather than
+// wrapping around
+func subtractUint64(a, b uint64) interface{} {
+	if a >= b {
+		return a - b
+	}
+	if d := b - a; d <= math.MaxInt64 {
+		return -int64(d)
+	} else if d == math.MaxInt64+1 {
+		return int64(math.MinInt64)
+	}
+	return float64(a) - float64(b)
+}
+
 // Substract v2 from v1 or return zero if a type issue
 func Subtract(v1, v2 interface{}) interface{} {
 	a, b, nType := normalizeNumbers(v1, v2)
@@ -233,6 +368,8 @@ func Subtract(v1, v2 interface{}) interface{} {
 		return a.(float32) - b.(float32)
 	case int64Type:
 		return a.(int64) - b.(int64)
+	case uint64Type:
+		return subtractUint64(a.(uint64), b.(uint64))
 	case float64Type:
 		return a.(float64) - b.(float64)
 	default:
@@ -250,6 +387,8 @@ func Multiply(v1, v2 interface{}) interface{} {
 		return a.(float32) * b.(float32)
 	case int64Type:
 		return a.(int64) * b.(int64)
+	case uint64Type:
+		return a.(uint64) * b.(uint64)
 	case float64Type:
 		return a.(float64) * b.(float64)
 	default:
@@ -260,9 +399,7 @@ func Multiply(v1, v2 interface{}) interface{} {
 // Divide v1 by v2 for non-zero v2 or return zero
 func Divide(v1, v2 interface{}) interface{} {
 	a, b, nType := normalizeNumbers(v1, v2)
-	//FIXME: this is ugly, divide by zero error will be logged
 	if IsZero(b) {
-		log.Printf("Divide by Zero error for values %T %v, %T %v\n", a, a, b, b)
 		return 0
 	}
 	switch nType {
@@ -272,6 +409,8 @@ func Divide(v1, v2 interface{}) interface{} {
 		return a.(float32) / b.(float32)
 	case int64Type:
 		return a.(int64) / b.(int64)
+	case uint64Type:
+		return a.(uint64) / b.(uint64)
 	case float64Type:
 		return a.(float64) / b.(float64)
 	default:
@@ -279,14 +418,20 @@ func Divide(v1, v2 interface{}) interface{} {
 	}
 }
 
-// Modulo returns the modulo of int or int64 or zero
+// Modulo returns the modulo of int or int64 or zero, including for a
+// zero v2
 func Modulo(v1, v2 interface{}) interface{} {
 	a, b, nType := normalizeNumbers(v1, v2)
+	if IsZero(b) {
+		return 0
+	}
 	switch nType {
 	case intType:
 		return a.(int) % b.(int)
 	case int64Type:
 		return a.(int64) % b.(int64)
+	case uint64Type:
+		return a.(uint64) % b.(uint64)
 	default:
 		return 0
 	}
@@ -309,6 +454,11 @@ func Int64(v interface{}) int64 {
 	return toType(v, int64Type).(int64)
 }
 
+// Uint64 returns a uint64 for value provided
+func Uint64(v interface{}) uint64 {
+	return toType(v, uint64Type).(uint64)
+}
+
 // Int returns a int for value provided
 func Int(v interface{}) int {
 	return toType(v, intType).(int)
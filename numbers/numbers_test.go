@@ -3,6 +3,7 @@ package numbers
 // this is the sketch
 import (
 	"encoding/json"
+	"math"
 	"testing"
 )
 
@@ -65,3 +66,164 @@ func TestTypeHandling(t *testing.T) {
 		t.Errorf("expected %d, got %T %v", e6, r6, r6)
 	}
 }
+
+type count uint32
+
+func TestIntegerKinds(t *testing.T) {
+	testSet := []struct {
+		a, b     interface{}
+		op       func(interface{}, interface{}) interface{}
+		expected interface{}
+	}{
+		{int8(2), int32(3), Add, 5},
+		{uint8(250), uint16(10), Add, 260},
+		{int16(-4), uint(6), Multiply, int64(-24)},
+		{count(7), 2, Add, int64(9)},
+		{uint(7), uint64(2), Add, uint64(9)},
+		{uint64(2), uint64(7), Subtract, int64(-5)},
+		{uint64(9), uint64(2), Subtract, uint64(7)},
+		{uint64(math.MaxUint64), -1, Add, float64(math.MaxUint64) - 1},
+		{uint64(math.MaxInt64) + 1, uint64(0), Subtract, uint64(math.MaxInt64) + 1},
+		{uint64(0), uint64(math.MaxInt64) + 1, Subtract, int64(math.MinInt64)},
+		{uint64(10), int8(3), Modulo, int64(1)},
+		{uint32(10), float32(0.5), Multiply, float32(5)},
+		{uintptr(9), int64(3), Divide, int64(3)},
+	}
+	for i, test := range testSet {
+		r := test.op(test.a, test.b)
+		if r != test.expected {
+			t.Errorf("(%d) %T %v, %T %v, expected %T %v, got %T %v", i, test.a, test.a, test.b, test.b, test.expected, test.expected, r, r)
+		}
+	}
+
+	// json.Number integers are exact
+	jsonSet := []struct {
+		r, expected interface{}
+	}{
+		{Add(ToDecimal("1"), 2), int64(3)},
+		{Multiply(json.Number("3"), json.Number("4")), int64(12)},
+		{Add(json.Number("18446744073709551615"), uint(0)), uint64(math.MaxUint64)},
+		{Add(json.Number("1.5"), 1), 2.5},
+		{Subtract(json.Number("1e3"), 1), float64(999)},
+	}
+	for i, test := range jsonSet {
+		if test.r != test.expected {
+			t.Errorf("(%d) expected %T %v, got %T %v", i, test.expected, test.expected, test.r, test.r)
+		}
+	}
+	if Equal(int64(9007199254740993), json.Number("9007199254740992")) {
+		t.Errorf("expected 9007199254740993 and 9007199254740992 to differ")
+	}
+
+	if IsNumber(count(1)) == false || IsNumber("1") {
+		t.Errorf("expected named integer types to be numbers and strings not to be")
+	}
+	if IsZero(uint16(0)) == false || IsZero(count(3)) {
+		t.Errorf("IsZero didn't handle unsigned values")
+	}
+	if r := Uint64(int8(4)); r != 4 {
+		t.Errorf("expected 4, got %d", r)
+	}
+	if d, err := ParseDecimal(uint64(math.MaxUint64)); err != nil || d.String() != "18446744073709551615" {
+		t.Errorf("expected 18446744073709551615, got %s, %v", d, err)
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	testSet := []struct {
+		src      string
+		expected interface{}
+	}{
+		{"42", int64(42)},
+		{" +7 ", int64(7)},
+		{"-1,234,567", int64(-1234567)},
+		{"1,024.25", 1024.25},
+		{"6.02e23", 6.02e23},
+		{"-.5", -0.5},
+		{"1E-3", 0.001},
+		{"18446744073709551615", uint64(math.MaxUint64)},
+		{"99999999999999999999", 1e20},
+	}
+	for _, test := range testSet {
+		r, err := ParseNumber(test.src)
+		if err != nil {
+			t.Errorf("%q, unexpected error %s", test.src, err)
+		} else if r != test.expected {
+			t.Errorf("%q, expected %T %v, got %T %v", test.src, test.expected, test.expected, r, r)
+		}
+	}
+	for _, src := range []string{"", "abc", "1,23", "12,3456", "1.2.3", "NaN", "Inf", "0x10", "1e400", "++1"} {
+		if _, err := ParseNumber(src); err == nil {
+			t.Errorf("%q, expected an error", src)
+		}
+	}
+
+	if r := Add("2", int8(3)); r != int64(5) {
+		t.Errorf("expected int64 5, got %T %v", r, r)
+	}
+	if r := Multiply("1.5", "2"); r != float64(3) {
+		t.Errorf("expected float64 3, got %T %v", r, r)
+	}
+	if r := Add("n/a", 1); r != 1 {
+		t.Errorf("expected 1, got %T %v", r, r)
+	}
+	if r := Int("1,024"); r != 1024 {
+		t.Errorf("expected 1024, got %d", r)
+	}
+	if IsNumeric("1e3") == false || IsNumeric("") || IsNumeric(uint8(1)) == false {
+		t.Errorf("IsNumeric returned unexpected results")
+	}
+	if d, err := ParseDecimal("+1,000.50"); err != nil || d.String() != "1000.50" {
+		t.Errorf("expected 1000.50, got %s, %v", d, err)
+	}
+}
+
+func TestAggregates(t *testing.T) {
+	list := []interface{}{json.Number("3"), 1, "4", 1.5, "n/a", int8(1), uint(9), nil}
+	if r := Sum(list); r != 19.5 {
+		t.Errorf("expected 19.5, got %T %v", r, r)
+	}
+	if r := Sum([]int{1, 2, 3}); r != 6 {
+		t.Errorf("expected 6, got %T %v", r, r)
+	}
+	if r := Count(list); r != 6 {
+		t.Errorf("expected 6, got %d", r)
+	}
+	if r := Mean(list); r != 3.25 {
+		t.Errorf("expected 3.25, got %g", r)
+	}
+	if r := Median(list); r != 2.25 {
+		t.Errorf("expected 2.25, got %T %v", r, r)
+	}
+	if r := Median([]int64{5, 1, 3}); r != int64(3) {
+		t.Errorf("expected 3, got %T %v", r, r)
+	}
+	if r := Mode(list); r != 1 {
+		t.Errorf("expected 1, got %T %v", r, r)
+	}
+	if r := Minimum(list); r != 1 {
+		t.Errorf("expected 1, got %T %v", r, r)
+	}
+	if r := Maximum(list); r != uint(9) {
+		t.Errorf("expected 9, got %T %v", r, r)
+	}
+	// NaN and infinities are skipped like values that aren't numbers
+	withNaN := []interface{}{1.0, math.NaN(), 3, math.Inf(1), "NaN", float32(math.Inf(-1))}
+	if r := Sum(withNaN); r != 4.0 {
+		t.Errorf("expected 4, got %T %v", r, r)
+	}
+	if r := Mean(withNaN); r != 2 {
+		t.Errorf("expected 2, got %g", r)
+	}
+	if r := Median(withNaN); r != 2.0 {
+		t.Errorf("expected 2, got %T %v", r, r)
+	}
+	if r, err := (Policy{Strict: true}).Sum(withNaN); err != nil || r != 4.0 {
+		t.Errorf("expected 4, got %T %v, %v", r, r, err)
+	}
+	for _, empty := range []interface{}{nil, []string{}, "1,2,3"} {
+		if Sum(empty) != 0 || Count(empty) != 0 || Mean(empty) != 0 || Median(empty) != 0 || Mode(empty) != 0 || Minimum(empty) != 0 || Maximum(empty) != 0 {
+			t.Errorf("expected zeros for %v", empty)
+		}
+	}
+}
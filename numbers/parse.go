@@ -0,0 +1,55 @@
+package numbers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// reNumeric matches an optionally signed integer or decimal with an
+// optional exponent. The integer part may use commas to group thousands
+// (e.g. 1,234,567.89) but the groups must be complete.
+var reNumeric = regexp.MustCompile(`^[+-]?(([0-9]+|[0-9]{1,3}(,[0-9]{3})+)(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// cleanNumber trims spaces, thousands separators and a leading "+" from
+// s, it returns false if s isn't a number.
+func cleanNumber(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if reNumeric.MatchString(s) == false {
+		return "", false
+	}
+	return strings.TrimPrefix(strings.Replace(s, ",", "", -1), "+"), true
+}
+
+// ParseNumber parses a numeric string such as "42", "+1,024", "-0.5" or
+// "6.02e23". Integers are returned as an int64, or a uint64 if too large
+// for an int64, everything else is returned as a float64.
+func ParseNumber(s string) (interface{}, error) {
+	src, ok := cleanNumber(s)
+	if ok == false {
+		return nil, fmt.Errorf("%q is not a number", s)
+	}
+	if strings.ContainsAny(src, ".eE") == false {
+		if i, err := strconv.ParseInt(src, 10, 64); err == nil {
+			return i, nil
+		}
+		if u, err := strconv.ParseUint(src, 10, 64); err == nil {
+			return u, nil
+		}
+	}
+	f, err := strconv.ParseFloat(src, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is out of range", s)
+	}
+	return f, nil
+}
+
+// IsNumeric returns true if v is a number or a string ParseNumber can read
+func IsNumeric(v interface{}) bool {
+	if s, ok := v.(string); ok {
+		_, err := ParseNumber(s)
+		return err == nil
+	}
+	return IsNumber(v)
+}
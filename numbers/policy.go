@@ -0,0 +1,326 @@
+package numbers
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// OverflowPolicy decides what happens when integer arithmetic overflows
+// its type, e.g. adding two large int64 values
+type OverflowPolicy int
+
+const (
+	// OverflowError returns an error
+	OverflowError OverflowPolicy = iota
+	// OverflowFloat returns the result as a float64
+	OverflowFloat
+	// OverflowBig returns the exact result as a *big.Int, the Policy
+	// methods accept it as an integer so chained results stay exact
+	OverflowBig
+	// OverflowWrap wraps around like Go's integer arithmetic (and Add,
+	// Multiply, etc.) do
+	OverflowWrap
+)
+
+// NaNPolicy decides what happens when a float result is NaN or an
+// infinity, e.g. the square root of -1 or 1e308 * 10
+type NaNPolicy int
+
+const (
+	// NaNError returns an error
+	NaNError NaNPolicy = iota
+	// NaNZero returns zero
+	NaNZero
+	// NaNPlaceholder returns the Policy's Placeholder string
+	NaNPlaceholder
+	// NaNAllow returns the value, it renders as NaN, +Inf or -Inf
+	NaNAllow
+)
+
+// Policy holds the overflow and NaN/Inf policies applied by its
+// arithmetic methods. The zero value returns errors for both.
+type Policy struct {
+	Overflow OverflowPolicy
+	NaN      NaNPolicy
+	// Placeholder is returned for NaN and infinite results by
+	// NaNPlaceholder, e.g. "n/a" or "—"
+	Placeholder string
+	// Strict makes values that aren't numbers and division by zero
+	// errors (like AddErr and DivideErr) rather than zero
+	Strict bool
+}
+
+// Result applies the NaN policy to v, values that aren't NaN or
+// infinite floats are returned as is
+func (p Policy) Result(v interface{}) (interface{}, error) {
+	var f float64
+	switch x := v.(type) {
+	case float64:
+		f = x
+	case float32:
+		f = float64(x)
+	default:
+		return v, nil
+	}
+	if math.IsNaN(f) == false && math.IsInf(f, 0) == false {
+		return v, nil
+	}
+	switch p.NaN {
+	case NaNZero:
+		if _, ok := v.(float32); ok {
+			return float32(0), nil
+		}
+		return float64(0), nil
+	case NaNPlaceholder:
+		return p.Placeholder, nil
+	case NaNAllow:
+		return v, nil
+	}
+	return nil, fmt.Errorf("result is %g", f)
+}
+
+// toBig returns an integer working value as a *big.Int
+func toBig(v interface{}) *big.Int {
+	switch x := v.(type) {
+	case int:
+		return big.NewInt(int64(x))
+	case int64:
+		return big.NewInt(x)
+	case uint64:
+		return new(big.Int).SetUint64(x)
+	}
+	return new(big.Int)
+}
+
+// fits returns r as nType if it fits, a negative result of unsigned
+// arithmetic fits if it is an int64 (as in Subtract)
+func fits(r *big.Int, nType int) (interface{}, bool) {
+	switch nType {
+	case intType:
+		if r.IsInt64() && int64(int(r.Int64())) == r.Int64() {
+			return int(r.Int64()), true
+		}
+	case int64Type:
+		if r.IsInt64() {
+			return r.Int64(), true
+		}
+	case uint64Type:
+		if r.IsUint64() {
+			return r.Uint64(), true
+		}
+		if r.Sign() < 0 && r.IsInt64() {
+			return r.Int64(), true
+		}
+	}
+	return nil, false
+}
+
+// overflow applies the overflow policy to the exact result r, wrapped
+// is the result Go's arithmetic gives
+func (p Policy) overflow(op string, v1, v2 interface{}, r *big.Int, wrapped func() interface{}) (interface{}, error) {
+	switch p.Overflow {
+	case OverflowFloat:
+		f, _ := new(big.Float).SetInt(r).Float64()
+		return f, nil
+	case OverflowBig:
+		return r, nil
+	case OverflowWrap:
+		return wrapped(), nil
+	}
+	return nil, fmt.Errorf("%s: %v and %v overflow", op, v1, v2)
+}
+
+// integerOperands returns v1 and v2 as *big.Int values and the type
+// of their result if both are integers. A *big.Int operand (e.g. an
+// OverflowBig result) or a uint64 too large for an int64 mixed with a
+// signed integer gives naNType, its result is an int64 or uint64 if it
+// fits.
+func integerOperands(v1, v2 interface{}) (*big.Int, *big.Int, int, bool) {
+	_, big1 := v1.(*big.Int)
+	_, big2 := v2.(*big.Int)
+	if big1 || big2 {
+		x, y := bigOperand(v1), bigOperand(v2)
+		return x, y, naNType, x != nil && y != nil
+	}
+	a, b, nType := normalizeNumbers(v1, v2)
+	if nType == intType || nType == int64Type || nType == uint64Type {
+		return toBig(a), toBig(b), nType, true
+	}
+	// normalizeNumbers makes the mixed signed and unsigned case a float64
+	if x, y := bigOperand(v1), bigOperand(v2); x != nil && y != nil {
+		return x, y, naNType, true
+	}
+	return nil, nil, nType, false
+}
+
+// wrapUint64 returns the low 64 bits of r, the result Go's uint64
+// arithmetic gives
+func wrapUint64(r *big.Int) uint64 {
+	return new(big.Int).And(r, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+}
+
+// bigOperand returns an integer v as a *big.Int or nil
+func bigOperand(v interface{}) *big.Int {
+	if x, ok := v.(*big.Int); ok {
+		return x
+	}
+	switch nType := valueType(v); nType {
+	case intType, int64Type, uint64Type:
+		return toBig(toType(v, nType))
+	}
+	return nil
+}
+
+// arithmetic does op on v1 and v2 checking integer results for
+// overflow and float results for NaN and infinities, lenient is the
+// unchecked function for op (e.g. Add)
+func (p Policy) arithmetic(op string, v1, v2 interface{}, lenient func(interface{}, interface{}) interface{}) (interface{}, error) {
+	if p.Strict {
+		if err := checkNumbers(op, v1, v2); err != nil {
+			return nil, err
+		}
+		if op == "divide" || op == "modulo" {
+			if err := checkDivisor(op, v2); err != nil {
+				return nil, err
+			}
+		}
+	}
+	wrapped := func() interface{} {
+		return lenient(v1, v2)
+	}
+	if x, y, nType, ok := integerOperands(v1, v2); ok {
+		var r *big.Int
+		switch op {
+		case "add":
+			r = new(big.Int).Add(x, y)
+		case "subtract":
+			r = new(big.Int).Sub(x, y)
+		case "multiply":
+			r = new(big.Int).Mul(x, y)
+		case "divide":
+			if y.Sign() == 0 {
+				return wrapped(), nil
+			}
+			r = new(big.Int).Quo(x, y)
+		case "modulo":
+			if y.Sign() == 0 {
+				return wrapped(), nil
+			}
+			r = new(big.Int).Rem(x, y)
+		case "pow":
+			// A result over 4096 bits isn't promoted to a *big.Int
+			var ok bool
+			if r, ok = bigPow(x, y, 4096); ok == false {
+				if y.Sign() < 0 {
+					return p.Result(Pow(v1, v2))
+				}
+				if p.Overflow == OverflowBig {
+					return nil, fmt.Errorf("%s: %v and %v overflow", op, v1, v2)
+				}
+				return p.tooLarge(op, v1, v2, wrapped)
+			}
+		default:
+			return wrapped(), nil
+		}
+		if nType == naNType {
+			// *big.Int and mixed operands give whichever integer type fits
+			if v, ok := fits(r, int64Type); ok {
+				return v, nil
+			}
+			if v, ok := fits(r, uint64Type); ok {
+				return v, nil
+			}
+			wrapped = func() interface{} {
+				return wrapUint64(r)
+			}
+		} else if v, ok := fits(r, nType); ok {
+			return v, nil
+		}
+		return p.overflow(op, v1, v2, r, wrapped)
+	}
+	if op == "modulo" {
+		// Modulo of floats is always zero, for compatibility
+		return wrapped(), nil
+	}
+	return p.Result(wrapped())
+}
+
+// tooLarge handles an integer power too large to calculate exactly
+func (p Policy) tooLarge(op string, v1, v2 interface{}, wrapped func() interface{}) (interface{}, error) {
+	switch p.Overflow {
+	case OverflowFloat, OverflowWrap:
+		return p.Result(wrapped())
+	}
+	return nil, fmt.Errorf("%s: %v and %v overflow", op, v1, v2)
+}
+
+// Add adds v1 and v2 like Add applying the policies
+func (p Policy) Add(v1, v2 interface{}) (interface{}, error) {
+	return p.arithmetic("add", v1, v2, Add)
+}
+
+// Subtract subtracts v2 from v1 like Subtract applying the policies
+func (p Policy) Subtract(v1, v2 interface{}) (interface{}, error) {
+	return p.arithmetic("subtract", v1, v2, Subtract)
+}
+
+// Multiply multiplies v1 by v2 like Multiply applying the policies
+func (p Policy) Multiply(v1, v2 interface{}) (interface{}, error) {
+	return p.arithmetic("multiply", v1, v2, Multiply)
+}
+
+// Divide divides v1 by v2 like Divide applying the policies, the most
+// negative int64 divided by -1 overflows
+func (p Policy) Divide(v1, v2 interface{}) (interface{}, error) {
+	return p.arithmetic("divide", v1, v2, Divide)
+}
+
+// Modulo returns the remainder of v1 divided by v2 like Modulo, or like
+// ModuloErr if the policy is Strict (so floats have a remainder)
+func (p Policy) Modulo(v1, v2 interface{}) (interface{}, error) {
+	if _, _, _, integers := integerOperands(v1, v2); p.Strict && integers == false {
+		r, err := ModuloErr(v1, v2)
+		if err != nil {
+			return nil, err
+		}
+		return p.Result(r)
+	}
+	return p.arithmetic("modulo", v1, v2, Modulo)
+}
+
+// Pow raises base to exp like Pow applying the policies, integer powers
+// that overflow follow the overflow policy rather than becoming floats
+func (p Policy) Pow(base, exp interface{}) (interface{}, error) {
+	return p.arithmetic("pow", base, exp, Pow)
+}
+
+// Sqrt returns the square root of v applying the NaN policy, if the
+// policy is Strict negative values are an error like SqrtErr
+func (p Policy) Sqrt(v interface{}) (interface{}, error) {
+	if p.Strict {
+		r, err := SqrtErr(v)
+		if err != nil {
+			return nil, err
+		}
+		return p.Result(r)
+	}
+	return p.Result(Sqrt(v))
+}
+
+// Sum adds the numeric values in list like Sum applying the policies
+func (p Policy) Sum(list interface{}) (interface{}, error) {
+	// NaN and infinities are handled once the sum is done
+	q := p
+	q.NaN = NaNAllow
+	var (
+		total interface{} = 0
+		err   error
+	)
+	for _, v := range Values(list) {
+		if total, err = q.Add(total, v); err != nil {
+			return nil, err
+		}
+	}
+	return p.Result(total)
+}
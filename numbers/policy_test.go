@@ -0,0 +1,189 @@
+package numbers
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestPolicyOverflow(t *testing.T) {
+	big63, _ := new(big.Int).SetString("9223372036854775808", 10)
+	testSet := []struct {
+		p        Policy
+		fn       func(Policy, interface{}, interface{}) (interface{}, error)
+		a, b     interface{}
+		expected interface{}
+	}{
+		{Policy{}, Policy.Add, 2, 3, 5},
+		{Policy{Overflow: OverflowFloat}, Policy.Add, int64(math.MaxInt64), 1, float64(math.MaxInt64) + 1},
+		{Policy{Overflow: OverflowWrap}, Policy.Add, int64(math.MaxInt64), 1, int64(math.MinInt64)},
+		{Policy{Overflow: OverflowFloat}, Policy.Multiply, uint64(math.MaxUint64), uint64(2), float64(math.MaxUint64) * 2},
+		{Policy{Overflow: OverflowFloat}, Policy.Divide, int64(math.MinInt64), -1, float64(1 << 63)},
+		{Policy{}, Policy.Subtract, uint64(1), uint64(3), int64(-2)},
+		{Policy{}, Policy.Pow, 2, 62, 1 << 62},
+		{Policy{Overflow: OverflowFloat}, Policy.Pow, 2, 64, math.Pow(2, 64)},
+		{Policy{}, Policy.Pow, 2, -2, 0.25},
+		{Policy{}, Policy.Pow, -1, int64(math.MaxInt64), int64(-1)},
+		{Policy{Overflow: OverflowFloat}, Policy.Pow, 10, 100000, math.Inf(1)},
+	}
+	for i, test := range testSet {
+		if test.expected == math.Inf(1) {
+			test.p.NaN = NaNAllow
+		}
+		r, err := test.fn(test.p, test.a, test.b)
+		if err != nil {
+			t.Errorf("(%d) unexpected error %s", i, err)
+		} else if r != test.expected {
+			t.Errorf("(%d) expected %T %v, got %T %v", i, test.expected, test.expected, r, r)
+		}
+	}
+
+	r, err := Policy{Overflow: OverflowBig}.Add(int64(math.MaxInt64), 1)
+	if b, ok := r.(*big.Int); err != nil || ok == false || b.Cmp(big63) != 0 {
+		t.Errorf("expected *big.Int %s, got %T %v, %v", big63, r, r, err)
+	}
+	// A *big.Int result can be used in further arithmetic
+	if r := Subtract(r, uint(1)); r != uint64(math.MaxInt64) {
+		t.Errorf("expected %d, got %T %v", uint64(math.MaxInt64), r, r)
+	}
+	for i, fn := range []func() (interface{}, error){
+		func() (interface{}, error) { return Policy{}.Add(int64(math.MaxInt64), 1) },
+		func() (interface{}, error) { return Policy{}.Multiply(math.MaxInt32, math.MaxInt64) },
+		func() (interface{}, error) { return Policy{}.Divide(int64(math.MinInt64), -1) },
+		func() (interface{}, error) { return Policy{}.Pow(3, 1000000000) },
+		func() (interface{}, error) { return Policy{Strict: true}.Add("n/a", 1) },
+		func() (interface{}, error) { return Policy{Strict: true}.Divide(1, 0) },
+	} {
+		if r, err := fn(); err == nil {
+			t.Errorf("(%d) expected an error, got %T %v", i, r, r)
+		}
+	}
+}
+
+func TestPolicyNaN(t *testing.T) {
+	testSet := []struct {
+		p        Policy
+		expected interface{}
+	}{
+		{Policy{NaN: NaNZero}, float64(0)},
+		{Policy{NaN: NaNPlaceholder, Placeholder: "n/a"}, "n/a"},
+	}
+	for _, test := range testSet {
+		if r, err := test.p.Multiply(1e308, 10.0); err != nil || r != test.expected {
+			t.Errorf("expected %v, got %v, %v", test.expected, r, err)
+		}
+		if r, err := test.p.Sqrt(-1); err != nil || r != test.expected {
+			t.Errorf("expected %v, got %v, %v", test.expected, r, err)
+		}
+	}
+	if r, err := (Policy{NaN: NaNAllow}).Sqrt(-1); err != nil || math.IsNaN(r.(float64)) == false {
+		t.Errorf("expected NaN, got %v, %v", r, err)
+	}
+	if _, err := (Policy{}).Multiply(1e308, 10.0); err == nil {
+		t.Errorf("expected an error for +Inf")
+	}
+	if _, err := (Policy{Strict: true, NaN: NaNZero}).Sqrt(-1); err == nil {
+		t.Errorf("expected a strict error for a negative square root")
+	}
+	if r, err := (Policy{}).Result(float32(1.5)); err != nil || r != float32(1.5) {
+		t.Errorf("expected 1.5, got %v, %v", r, err)
+	}
+}
+
+func TestPolicyBigOperands(t *testing.T) {
+	p := Policy{Overflow: OverflowBig, Strict: true}
+	big63, _ := new(big.Int).SetString("9223372036854775808", 10)
+	r, err := p.Multiply(uint64(math.MaxUint64), uint64(2))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	// Chaining an OverflowBig result stays exact
+	if r, err = p.Add(r, 1); err != nil || r.(*big.Int).String() != "36893488147419103231" {
+		t.Errorf("expected 36893488147419103231, got %v, %v", r, err)
+	}
+	r2, err := p.Subtract(r, uint64(math.MaxUint64))
+	if err != nil || r2.(*big.Int).String() != "18446744073709551616" {
+		t.Errorf("expected 18446744073709551616, got %v, %v", r2, err)
+	}
+	if r2, err := p.Subtract(r2, 1); err != nil || r2 != uint64(math.MaxUint64) {
+		t.Errorf("expected uint64 %d, got %T %v, %v", uint64(math.MaxUint64), r2, r2, err)
+	}
+	if r2, err := p.Subtract(big63, 1); err != nil || r2 != int64(math.MaxInt64) {
+		t.Errorf("expected int64 %d, got %T %v, %v", int64(math.MaxInt64), r2, r2, err)
+	}
+	if r2, err := p.Multiply(r, json.Number("2")); err != nil || r2.(*big.Int).String() != "73786976294838206462" {
+		t.Errorf("expected 73786976294838206462, got %v, %v", r2, err)
+	}
+	if r2, err := p.Modulo(r, 10); err != nil || r2 != int64(1) {
+		t.Errorf("expected 1, got %T %v, %v", r2, r2, err)
+	}
+	if r2, err := p.Add(r, 0.5); err != nil || r2 != 36893488147419103231.5 {
+		t.Errorf("expected a float64, got %T %v, %v", r2, r2, err)
+	}
+}
+
+func TestPolicyMixedSigns(t *testing.T) {
+	maxUint := uint64(math.MaxUint64)
+	two64, _ := new(big.Int).SetString("18446744073709551616", 10)
+	testSet := []struct {
+		op       string
+		v1, v2   interface{}
+		big      string
+		wrap     uint64
+		expected float64
+	}{
+		{"add", maxUint, 1, "18446744073709551616", 0, 18446744073709551616},
+		{"multiply", maxUint, -1, "-18446744073709551615", 1, -18446744073709551615},
+		{"subtract", maxUint, int64(-3), "18446744073709551618", 2, 18446744073709551618},
+	}
+	for i, test := range testSet {
+		ops := map[string]func(Policy, interface{}, interface{}) (interface{}, error){
+			"add":      Policy.Add,
+			"multiply": Policy.Multiply,
+			"subtract": Policy.Subtract,
+		}
+		fn := ops[test.op]
+		if r, err := fn(Policy{Strict: true}, test.v1, test.v2); err == nil {
+			t.Errorf("(%d) expected an overflow error, got %T %v", i, r, r)
+		}
+		if r, err := fn(Policy{Overflow: OverflowFloat}, test.v1, test.v2); err != nil || r != test.expected {
+			t.Errorf("(%d) expected %g, got %T %v, %v", i, test.expected, r, r, err)
+		}
+		if r, err := fn(Policy{Overflow: OverflowBig}, test.v1, test.v2); err != nil || r.(*big.Int).String() != test.big {
+			t.Errorf("(%d) expected %s, got %T %v, %v", i, test.big, r, r, err)
+		}
+		if r, err := fn(Policy{Overflow: OverflowWrap}, test.v1, test.v2); err != nil || r != test.wrap {
+			t.Errorf("(%d) expected uint64 %d, got %T %v, %v", i, test.wrap, r, r, err)
+		}
+	}
+	// Mixed results that fit are an int64 or a uint64
+	if r, err := (Policy{Strict: true}).Subtract(uint64(math.MaxInt64)+5, int64(-3)); err != nil || r != uint64(math.MaxInt64)+8 {
+		t.Errorf("expected uint64 %d, got %T %v, %v", uint64(math.MaxInt64)+8, r, r, err)
+	}
+	if r, err := (Policy{Strict: true}).Add(maxUint, -1); err != nil || r != maxUint-1 {
+		t.Errorf("expected uint64 %d, got %T %v, %v", maxUint-1, r, r, err)
+	}
+	if r, err := (Policy{Strict: true}).Subtract(int64(-1), maxUint); err == nil {
+		t.Errorf("expected an overflow error, got %T %v", r, r)
+	}
+	if r, err := (Policy{Strict: true}).Subtract(maxUint, two64); err != nil || r != int64(-1) {
+		t.Errorf("expected int64 -1, got %T %v, %v", r, r, err)
+	}
+}
+
+func TestPolicySum(t *testing.T) {
+	list := []interface{}{int64(math.MaxInt64), 1, "n/a"}
+	if r, err := (Policy{Strict: true}).Sum(list); err == nil {
+		t.Errorf("expected an overflow error, got %v", r)
+	}
+	if r, err := (Policy{Overflow: OverflowFloat}).Sum(list); err != nil || r != float64(math.MaxInt64)+1 {
+		t.Errorf("expected %g, got %v, %v", float64(math.MaxInt64)+1, r, err)
+	}
+	if r, err := (Policy{Strict: true}).Sum([]int{1, 2, 3}); err != nil || r != 6 {
+		t.Errorf("expected 6, got %v, %v", r, err)
+	}
+	if r, err := (Policy{NaN: NaNPlaceholder, Placeholder: "n/a"}).Sum([]float64{math.MaxFloat64, math.MaxFloat64, 1}); err != nil || r != "n/a" {
+		t.Errorf("expected n/a, got %v, %v", r, err)
+	}
+}
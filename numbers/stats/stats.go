@@ -0,0 +1,201 @@
+// Package stats provides descriptive statistics and histogram binning
+// over lists of numbers. Lists may hold any value the numbers package
+// understands (e.g. []int, []float64 or []interface{} decoded from
+// JSON), values that aren't numbers, NaN and infinities are skipped.
+// Empty lists return zero values rather than errors so the functions
+// can be used directly in templates.
+package stats
+
+import (
+	"math"
+	"sort"
+
+	// Caltech Library packages
+	"github.com/caltechlibrary/tmplfn/numbers"
+)
+
+// MaxBins is the most groups Quantiles, Histogram and QuantileHistogram
+// will divide a list into, a larger n is reduced to MaxBins
+const MaxBins = 10000
+
+// Bin is one bucket of a histogram, it holds the values greater than
+// or equal to Lower and less than Upper. The last bin of a histogram
+// also holds values equal to its Upper bound.
+type Bin struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+}
+
+// floats returns the finite numeric values of list as float64 in
+// ascending order. numbers.Values skips NaN and infinities, this also
+// drops a *big.Int too large for a float64.
+func floats(list interface{}) []float64 {
+	values := numbers.Values(list)
+	xs := make([]float64, 0, len(values))
+	for _, v := range values {
+		if x := numbers.Float64(v); math.IsNaN(x) == false && math.IsInf(x, 0) == false {
+			xs = append(xs, x)
+		}
+	}
+	sort.Float64s(xs)
+	return xs
+}
+
+// mean returns the average of xs, xs must not be empty
+func mean(xs []float64) float64 {
+	total := 0.0
+	for _, x := range xs {
+		total += x
+	}
+	return total / float64(len(xs))
+}
+
+// sumOfSquares returns the sum of squared differences from the mean
+func sumOfSquares(xs []float64) float64 {
+	m := mean(xs)
+	total := 0.0
+	for _, x := range xs {
+		total += (x - m) * (x - m)
+	}
+	return total
+}
+
+// Variance returns the population variance of list or zero if empty
+func Variance(list interface{}) float64 {
+	xs := floats(list)
+	if len(xs) == 0 {
+		return 0
+	}
+	return sumOfSquares(xs) / float64(len(xs))
+}
+
+// SampleVariance returns the sample variance (n - 1 denominator) of list
+// or zero if list has fewer than two values
+func SampleVariance(list interface{}) float64 {
+	xs := floats(list)
+	if len(xs) < 2 {
+		return 0
+	}
+	return sumOfSquares(xs) / float64(len(xs)-1)
+}
+
+// StdDev returns the population standard deviation of list
+func StdDev(list interface{}) float64 {
+	return math.Sqrt(Variance(list))
+}
+
+// SampleStdDev returns the sample standard deviation of list
+func SampleStdDev(list interface{}) float64 {
+	return math.Sqrt(SampleVariance(list))
+}
+
+// quantile interpolates linearly between the closest ranks of the
+// sorted values xs (the method used by R's default and NumPy). q is
+// clamped to [0, 1].
+func quantile(xs []float64, q float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	q = math.Max(0, math.Min(1, q))
+	h := float64(len(xs)-1) * q
+	lo := math.Floor(h)
+	i := int(lo)
+	if i+1 >= len(xs) {
+		return xs[len(xs)-1]
+	}
+	return xs[i] + (h-lo)*(xs[i+1]-xs[i])
+}
+
+// Quantile returns the q quantile (0 <= q <= 1) of list, e.g. 0.5 is the
+// median. An empty list returns zero.
+func Quantile(list interface{}, q float64) float64 {
+	return quantile(floats(list), q)
+}
+
+// Percentile returns the p percentile (0 <= p <= 100) of list
+func Percentile(list interface{}, p float64) float64 {
+	return quantile(floats(list), p/100)
+}
+
+// Quantiles returns the n - 1 cut points dividing list into n groups of
+// equal size, e.g. n of 4 returns the quartiles. An empty list or n less
+// than two returns an empty slice.
+func Quantiles(list interface{}, n int) []float64 {
+	xs := floats(list)
+	cuts := []float64{}
+	if len(xs) == 0 || n < 2 {
+		return cuts
+	}
+	if n > MaxBins {
+		n = MaxBins
+	}
+	for i := 1; i < n; i++ {
+		cuts = append(cuts, quantile(xs, float64(i)/float64(n)))
+	}
+	return cuts
+}
+
+// Histogram sorts list into n bins of equal width spanning the smallest
+// to largest value. If every value is the same a single bin is returned.
+// An empty list or n less than one returns an empty slice.
+func Histogram(list interface{}, n int) []Bin {
+	xs := floats(list)
+	bins := []Bin{}
+	if len(xs) == 0 || n < 1 {
+		return bins
+	}
+	if n > MaxBins {
+		n = MaxBins
+	}
+	lower, upper := xs[0], xs[len(xs)-1]
+	if lower == upper {
+		return append(bins, Bin{Lower: lower, Upper: upper, Count: len(xs)})
+	}
+	width := (upper - lower) / float64(n)
+	for i := 0; i < n; i++ {
+		bins = append(bins, Bin{Lower: lower + float64(i)*width, Upper: lower + float64(i+1)*width})
+	}
+	bins[n-1].Upper = upper
+	for _, x := range xs {
+		i := int((x - lower) / width)
+		switch {
+		case i < 0:
+			i = 0
+		case i >= n:
+			i = n - 1
+		}
+		bins[i].Count++
+	}
+	return bins
+}
+
+// QuantileHistogram sorts list into n bins holding (as near as ties
+// allow) the same number of values, the bin bounds are the quantiles of
+// list. An empty list or n less than one returns an empty slice.
+func QuantileHistogram(list interface{}, n int) []Bin {
+	xs := floats(list)
+	bins := []Bin{}
+	if len(xs) == 0 || n < 1 {
+		return bins
+	}
+	if n > MaxBins {
+		n = MaxBins
+	}
+	for i := 0; i < n; i++ {
+		bins = append(bins, Bin{
+			Lower: quantile(xs, float64(i)/float64(n)),
+			Upper: quantile(xs, float64(i+1)/float64(n)),
+		})
+	}
+	for _, x := range xs {
+		i := sort.Search(n, func(i int) bool {
+			return x < bins[i].Upper
+		})
+		if i >= n {
+			i = n - 1
+		}
+		bins[i].Count++
+	}
+	return bins
+}
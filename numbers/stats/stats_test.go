@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSpread(t *testing.T) {
+	list := []interface{}{2, 4, "4", json.Number("4"), 5, 5, 7, 9.0, "n/a"}
+	if r := Variance(list); closeTo(r, 4) == false {
+		t.Errorf("expected variance 4, got %g", r)
+	}
+	if r := StdDev(list); closeTo(r, 2) == false {
+		t.Errorf("expected stddev 2, got %g", r)
+	}
+	if r := SampleVariance(list); closeTo(r, 32.0/7.0) == false {
+		t.Errorf("expected sample variance %g, got %g", 32.0/7.0, r)
+	}
+	if r := SampleStdDev([]int{1}); r != 0 {
+		t.Errorf("expected 0 for a single value, got %g", r)
+	}
+	if r := Variance(nil); r != 0 {
+		t.Errorf("expected 0 for nil, got %g", r)
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	list := []int{15, 20, 35, 40, 50}
+	testSet := []struct {
+		q, expected float64
+	}{
+		{0, 15},
+		{0.25, 20},
+		{0.4, 29},
+		{0.5, 35},
+		{0.9, 46},
+		{1, 50},
+		{1.5, 50},
+	}
+	for _, test := range testSet {
+		if r := Quantile(list, test.q); closeTo(r, test.expected) == false {
+			t.Errorf("quantile %g, expected %g, got %g", test.q, test.expected, r)
+		}
+	}
+	if r := Percentile(list, 90); closeTo(r, 46) == false {
+		t.Errorf("expected 46, got %g", r)
+	}
+	cuts := Quantiles([]int{1, 2, 3, 4, 5, 6, 7, 8, 9}, 4)
+	expected := []float64{3, 5, 7}
+	if len(cuts) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, cuts)
+	}
+	for i := range expected {
+		if closeTo(cuts[i], expected[i]) == false {
+			t.Errorf("expected %v, got %v", expected, cuts)
+		}
+	}
+	if r := Quantiles(list, 1); len(r) != 0 {
+		t.Errorf("expected no cut points, got %v", r)
+	}
+}
+
+func TestHistograms(t *testing.T) {
+	list := []float64{0, 1, 2, 2.5, 3, 9, 10}
+	bins := Histogram(list, 5)
+	counts := []int{2, 3, 0, 0, 2}
+	if len(bins) != len(counts) {
+		t.Fatalf("expected %d bins, got %+v", len(counts), bins)
+	}
+	for i, c := range counts {
+		if bins[i].Count != c {
+			t.Errorf("bin %d, expected count %d, got %+v", i, c, bins[i])
+		}
+	}
+	if bins[1].Lower != 2 || bins[4].Upper != 10 {
+		t.Errorf("unexpected bounds %+v", bins)
+	}
+	if r := Histogram([]int{3, 3}, 4); len(r) != 1 || r[0].Count != 2 {
+		t.Errorf("expected a single bin of 2, got %+v", r)
+	}
+	bins = Histogram([]interface{}{1, math.NaN(), 2, math.Inf(1), 3, math.Inf(-1)}, 2)
+	if len(bins) != 2 || bins[0].Count+bins[1].Count != 3 || bins[0].Lower != 1 || bins[1].Upper != 3 {
+		t.Errorf("expected NaN and infinities to be skipped, got %+v", bins)
+	}
+	if r := Variance([]float64{1, math.NaN(), 3}); r != 1 {
+		t.Errorf("expected variance 1 without NaN, got %v", r)
+	}
+
+	if r := Histogram(list, 1000000000); len(r) != MaxBins {
+		t.Errorf("expected %d bins, got %d", MaxBins, len(r))
+	}
+	if r := QuantileHistogram(list, MaxBins+1); len(r) != MaxBins {
+		t.Errorf("expected %d bins, got %d", MaxBins, len(r))
+	}
+	if r := Quantiles(list, MaxBins*2); len(r) != MaxBins-1 {
+		t.Errorf("expected %d cut points, got %d", MaxBins-1, len(r))
+	}
+
+	bins = QuantileHistogram([]int{1, 2, 3, 4, 5, 6, 7, 8}, 4)
+	for i, b := range bins {
+		if b.Count != 2 {
+			t.Errorf("bin %d, expected 2 values, got %+v", i, b)
+		}
+	}
+	if r := QuantileHistogram(nil, 4); len(r) != 0 {
+		t.Errorf("expected no bins, got %+v", r)
+	}
+}
@@ -0,0 +1,368 @@
+package numbers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// The Err functions are the error returning versions of the numbers
+// operations. Where Add("n/a", 1) quietly treats "n/a" as zero and
+// Divide(1, 0) quietly returns zero, AddErr and DivideErr return an
+// error so a template's Execute fails with a clear message.
+
+// checkNumbers returns an error naming op for the first value that
+// isn't a number (see IsNumeric)
+func checkNumbers(op string, values ...interface{}) error {
+	for _, v := range values {
+		if IsNumeric(v) == false {
+			if v == nil {
+				return fmt.Errorf("%s: missing value, expected a number", op)
+			}
+			return fmt.Errorf("%s: %q (%T) is not a number", op, fmt.Sprintf("%v", v), v)
+		}
+	}
+	return nil
+}
+
+// checkDivisor returns an error naming op if v is zero
+func checkDivisor(op string, v interface{}) error {
+	if Equal(v, 0) {
+		return fmt.Errorf("%s: division by zero", op)
+	}
+	return nil
+}
+
+// AddErr adds v1 and v2 like Add or returns an error if either isn't a number
+func AddErr(v1, v2 interface{}) (interface{}, error) {
+	if err := checkNumbers("add", v1, v2); err != nil {
+		return nil, err
+	}
+	return Add(v1, v2), nil
+}
+
+// SubtractErr subtracts v2 from v1 like Subtract or returns an error if
+// either isn't a number
+func SubtractErr(v1, v2 interface{}) (interface{}, error) {
+	if err := checkNumbers("subtract", v1, v2); err != nil {
+		return nil, err
+	}
+	return Subtract(v1, v2), nil
+}
+
+// MultiplyErr multiplies v1 by v2 like Multiply or returns an error if
+// either isn't a number
+func MultiplyErr(v1, v2 interface{}) (interface{}, error) {
+	if err := checkNumbers("multiply", v1, v2); err != nil {
+		return nil, err
+	}
+	return Multiply(v1, v2), nil
+}
+
+// DivideErr divides v1 by v2 like Divide or returns an error if either
+// isn't a number or v2 is zero
+func DivideErr(v1, v2 interface{}) (interface{}, error) {
+	if err := checkNumbers("divide", v1, v2); err != nil {
+		return nil, err
+	}
+	if err := checkDivisor("divide", v2); err != nil {
+		return nil, err
+	}
+	return Divide(v1, v2), nil
+}
+
+// ModuloErr returns the remainder of v1 divided by v2 or an error if
+// either isn't a number or v2 is zero. Unlike Modulo, floats have a
+// remainder too (e.g. 7.5 and 2 give 1.5).
+func ModuloErr(v1, v2 interface{}) (interface{}, error) {
+	if err := checkNumbers("modulo", v1, v2); err != nil {
+		return nil, err
+	}
+	if err := checkDivisor("modulo", v2); err != nil {
+		return nil, err
+	}
+	a, b, nType := normalizeNumbers(v1, v2)
+	switch nType {
+	case float32Type:
+		return float32(math.Mod(float64(a.(float32)), float64(b.(float32)))), nil
+	case float64Type:
+		return math.Mod(a.(float64), b.(float64)), nil
+	}
+	return Modulo(v1, v2), nil
+}
+
+// AddiErr adds v1 and v2 returning an int like Addi or an error
+func AddiErr(v1, v2 interface{}) (int, error) {
+	if err := checkNumbers("addi", v1, v2); err != nil {
+		return 0, err
+	}
+	return Addi(v1, v2), nil
+}
+
+// SubiErr subtracts v2 from v1 returning an int like Subi or an error
+func SubiErr(v1, v2 interface{}) (int, error) {
+	if err := checkNumbers("subi", v1, v2); err != nil {
+		return 0, err
+	}
+	return Subi(v1, v2), nil
+}
+
+// checkInteger returns v as an int64 or an error naming op if v isn't
+// a number or is outside the range of an int64
+func checkInteger(op string, v interface{}) (int64, error) {
+	if err := checkNumbers(op, v); err != nil {
+		return 0, err
+	}
+	switch valueType(v) {
+	case uint64Type:
+		if toType(v, uint64Type).(uint64) > math.MaxInt64 {
+			return 0, fmt.Errorf("%s: %v is out of range", op, v)
+		}
+	case float32Type, float64Type:
+		// -2^63 is the smallest int64, 2^63 is one more than the largest
+		if f := toType(v, float64Type).(float64); math.IsNaN(f) || f < -(1<<63) || f >= 1<<63 {
+			return 0, fmt.Errorf("%s: %v is out of range", op, v)
+		}
+	}
+	return Int64(v), nil
+}
+
+// IntErr converts v to an int like Int or returns an error, values
+// outside the range of an int are an error rather than wrapping around
+func IntErr(v interface{}) (int, error) {
+	n, err := checkInteger("int", v)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(n)) != n {
+		return 0, fmt.Errorf("int: %v is out of range", v)
+	}
+	return int(n), nil
+}
+
+// Int64Err converts v to an int64 like Int64 or returns an error,
+// values outside the range of an int64 are an error rather than
+// wrapping around
+func Int64Err(v interface{}) (int64, error) {
+	return checkInteger("int64", v)
+}
+
+// Uint64Err converts v to a uint64 like Uint64 or returns an error,
+// negative values and values past the largest uint64 are an error
+// rather than wrapping around
+func Uint64Err(v interface{}) (uint64, error) {
+	if err := checkNumbers("uint64", v); err != nil {
+		return 0, err
+	}
+	if Less(v, 0) {
+		return 0, fmt.Errorf("uint64: %v is negative", v)
+	}
+	switch valueType(v) {
+	case float32Type, float64Type:
+		// 2^64 is one more than the largest uint64
+		if f := toType(v, float64Type).(float64); math.IsNaN(f) || f >= 1<<64 {
+			return 0, fmt.Errorf("uint64: %v is out of range", v)
+		}
+	}
+	return Uint64(v), nil
+}
+
+// Float32Err converts v to a float32 like Float32 or returns an error
+func Float32Err(v interface{}) (float32, error) {
+	if err := checkNumbers("float32", v); err != nil {
+		return 0, err
+	}
+	return Float32(v), nil
+}
+
+// Float64Err converts v to a float64 like Float64 or returns an error
+func Float64Err(v interface{}) (float64, error) {
+	if err := checkNumbers("float64", v); err != nil {
+		return 0, err
+	}
+	return Float64(v), nil
+}
+
+// RoundErr rounds v like Round or returns an error if v isn't a number
+func RoundErr(v interface{}, precision int, mode RoundingMode) (interface{}, error) {
+	if err := checkNumbers("round", v); err != nil {
+		return nil, err
+	}
+	if err := checkScale("round", precision); err != nil {
+		return nil, err
+	}
+	return Round(v, precision, mode), nil
+}
+
+// FloorValueErr is FloorValue returning an error if v isn't a number
+func FloorValueErr(v interface{}) (interface{}, error) {
+	if err := checkNumbers("floor", v); err != nil {
+		return nil, err
+	}
+	return FloorValue(v), nil
+}
+
+// CeilValueErr is CeilValue returning an error if v isn't a number
+func CeilValueErr(v interface{}) (interface{}, error) {
+	if err := checkNumbers("ceil", v); err != nil {
+		return nil, err
+	}
+	return CeilValue(v), nil
+}
+
+// AbsErr is Abs returning an error if v isn't a number
+func AbsErr(v interface{}) (interface{}, error) {
+	if err := checkNumbers("abs", v); err != nil {
+		return nil, err
+	}
+	return Abs(v), nil
+}
+
+// PowErr is Pow returning an error if a value isn't a number or the
+// result isn't a real number (e.g. the square root of -1)
+func PowErr(base, exp interface{}) (interface{}, error) {
+	if err := checkNumbers("pow", base, exp); err != nil {
+		return nil, err
+	}
+	r := Pow(base, exp)
+	if f, ok := r.(float64); ok && math.IsNaN(f) {
+		return nil, fmt.Errorf("pow: %v to the power %v is not a real number", base, exp)
+	}
+	return r, nil
+}
+
+// SqrtErr is Sqrt returning an error if v isn't a number or is negative
+func SqrtErr(v interface{}) (float64, error) {
+	if err := checkNumbers("sqrt", v); err != nil {
+		return 0, err
+	}
+	if Less(v, 0) {
+		return 0, fmt.Errorf("sqrt: %v is negative", v)
+	}
+	return Sqrt(v), nil
+}
+
+// MinErr is Min returning an error if a value isn't a number
+func MinErr(v1, v2 interface{}, more ...interface{}) (interface{}, error) {
+	if err := checkNumbers("min", append([]interface{}{v1, v2}, more...)...); err != nil {
+		return nil, err
+	}
+	return Min(v1, v2, more...), nil
+}
+
+// MaxErr is Max returning an error if a value isn't a number
+func MaxErr(v1, v2 interface{}, more ...interface{}) (interface{}, error) {
+	if err := checkNumbers("max", append([]interface{}{v1, v2}, more...)...); err != nil {
+		return nil, err
+	}
+	return Max(v1, v2, more...), nil
+}
+
+// ClampErr is Clamp returning an error if a value isn't a number or
+// low is greater than high
+func ClampErr(v, low, high interface{}) (interface{}, error) {
+	if err := checkNumbers("clamp", v, low, high); err != nil {
+		return nil, err
+	}
+	if Greater(low, high) {
+		return nil, fmt.Errorf("clamp: low %v is greater than high %v", low, high)
+	}
+	return Clamp(v, low, high), nil
+}
+
+// decimalPairErr converts two values to decimals naming op in the error
+func decimalPairErr(op string, v1, v2 interface{}) (Decimal, Decimal, error) {
+	a, err := ParseDecimal(v1)
+	if err != nil {
+		return a, a, fmt.Errorf("%s: %s", op, err)
+	}
+	b, err := ParseDecimal(v2)
+	if err != nil {
+		return a, b, fmt.Errorf("%s: %s", op, err)
+	}
+	return a, b, nil
+}
+
+// ToDecimalErr is ToDecimal returning an error if v isn't a number
+func ToDecimalErr(v interface{}) (json.Number, error) {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return json.Number("0"), fmt.Errorf("decimal: %s", err)
+	}
+	return d.Number(), nil
+}
+
+// DAddErr is DAdd returning an error if a value isn't a number
+func DAddErr(v1, v2 interface{}) (json.Number, error) {
+	a, b, err := decimalPairErr("dadd", v1, v2)
+	if err != nil {
+		return json.Number("0"), err
+	}
+	return a.Add(b).Number(), nil
+}
+
+// DSubtractErr is DSubtract returning an error if a value isn't a number
+func DSubtractErr(v1, v2 interface{}) (json.Number, error) {
+	a, b, err := decimalPairErr("dsub", v1, v2)
+	if err != nil {
+		return json.Number("0"), err
+	}
+	return a.Sub(b).Number(), nil
+}
+
+// DMultiplyErr is DMultiply returning an error if a value isn't a number
+func DMultiplyErr(v1, v2 interface{}) (json.Number, error) {
+	a, b, err := decimalPairErr("dmul", v1, v2)
+	if err != nil {
+		return json.Number("0"), err
+	}
+	return a.Mul(b).Number(), nil
+}
+
+// DDivideErr is DDivide returning an error if a value isn't a number
+// or v2 is zero
+func DDivideErr(v1, v2 interface{}) (json.Number, error) {
+	return DefaultDecimalContext.DivideErr(v1, v2)
+}
+
+// DivideErr is Divide returning an error if a value isn't a number or
+// v2 is zero
+func (ctx DecimalContext) DivideErr(v1, v2 interface{}) (json.Number, error) {
+	a, b, err := decimalPairErr("ddiv", v1, v2)
+	if err != nil {
+		return json.Number("0"), err
+	}
+	q, err := a.Div(b, ctx.Scale, ctx.Rounding)
+	if err != nil {
+		return json.Number("0"), fmt.Errorf("ddiv: %s", err)
+	}
+	return q.Number(), nil
+}
+
+// DRoundErr is DRound returning an error if v isn't a number or mode
+// isn't a rounding mode
+func DRoundErr(v interface{}, scale int, mode ...string) (json.Number, error) {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		return json.Number("0"), fmt.Errorf("dround: %s", err)
+	}
+	if err := checkScale("dround", scale); err != nil {
+		return json.Number("0"), err
+	}
+	m := HalfUp
+	if len(mode) > 0 {
+		if m, err = ParseRoundingMode(mode[0]); err != nil {
+			return json.Number("0"), fmt.Errorf("dround: %s", err)
+		}
+	}
+	return d.Round(scale, m).Number(), nil
+}
+
+// DCompareErr is DCompare returning an error if a value isn't a number
+func DCompareErr(v1, v2 interface{}) (int, error) {
+	a, b, err := decimalPairErr("dcmp", v1, v2)
+	if err != nil {
+		return 0, err
+	}
+	return a.Cmp(b), nil
+}
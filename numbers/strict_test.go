@@ -0,0 +1,93 @@
+package numbers
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+type errResult struct {
+	r        interface{}
+	err      error
+	expected interface{}
+}
+
+func TestErrVariants(t *testing.T) {
+	testSet := []errResult{}
+	add := func(r interface{}, err error, expected interface{}) {
+		testSet = append(testSet, errResult{r, err, expected})
+	}
+	r, err := AddErr("2", 3)
+	add(r, err, int64(5))
+	r, err = DivideErr(7, 2)
+	add(r, err, 3)
+	r, err = ModuloErr(7.5, 2)
+	add(r, err, 1.5)
+	r, err = ModuloErr(int64(7), 4)
+	add(r, err, int64(3))
+	i, err := IntErr(json.Number("4"))
+	add(i, err, 4)
+	u, err := Uint64Err("18446744073709551615")
+	add(u, err, uint64(18446744073709551615))
+	r, err = ClampErr(5, 0, 3)
+	add(r, err, 3)
+	d, err := DAddErr("0.1", "0.2")
+	add(d, err, json.Number("0.3"))
+	for i, test := range testSet {
+		if test.err != nil {
+			t.Errorf("(%d) unexpected error %s", i, test.err)
+		} else if test.r != test.expected {
+			t.Errorf("(%d) expected %T %v, got %T %v", i, test.expected, test.expected, test.r, test.r)
+		}
+	}
+
+	errorSet := map[string]error{}
+	_, errorSet["add"] = AddErr("n/a", 1)
+	_, errorSet["subtract"] = SubtractErr(1, nil)
+	_, errorSet["multiply"] = MultiplyErr(true, 1)
+	_, errorSet["divide"] = DivideErr(1, "0")
+	_, errorSet["modulo"] = ModuloErr(1, 0.0)
+	_, errorSet["uint64"] = Uint64Err(-1)
+	_, errorSet["pow"] = PowErr(-8, 0.5)
+	_, errorSet["sqrt"] = SqrtErr(-1)
+	_, errorSet["clamp"] = ClampErr(1, 3, 0)
+	_, errorSet["min"] = MinErr(1, 2, "x")
+	_, errorSet["dround"] = DRoundErr("1.5", 0, "sideways")
+	_, errorSet["ddiv"] = DDivideErr(1, 0)
+	_, errorSet["dcmp"] = DCompareErr("a", 1)
+	for op, err := range errorSet {
+		if err == nil {
+			t.Errorf("%s, expected an error", op)
+		} else if strings.HasPrefix(err.Error(), op+":") == false {
+			t.Errorf("%s, expected the error to name the operation, got %q", op, err)
+		}
+	}
+}
+
+func TestConversionRange(t *testing.T) {
+	huge, _ := new(big.Int).SetString("100000000000000000000", 10)
+	for _, v := range []interface{}{uint64(math.MaxUint64), 1e30, -1e30, math.NaN(), huge, json.Number("18446744073709551615")} {
+		if r, err := Int64Err(v); err == nil {
+			t.Errorf("int64 %v, expected an error, got %d", v, r)
+		}
+		if r, err := IntErr(v); err == nil {
+			t.Errorf("int %v, expected an error, got %d", v, r)
+		}
+	}
+	for _, v := range []interface{}{1e30, math.NaN(), math.Inf(1), huge} {
+		if r, err := Uint64Err(v); err == nil {
+			t.Errorf("uint64 %v, expected an error, got %d", v, r)
+		}
+	}
+	if r, err := Int64Err(uint64(math.MaxInt64)); err != nil || r != math.MaxInt64 {
+		t.Errorf("expected %d, got %d, %v", int64(math.MaxInt64), r, err)
+	}
+	if r, err := IntErr(-2.5); err != nil || r != -2 {
+		t.Errorf("expected -2, got %d, %v", r, err)
+	}
+	if r, err := Uint64Err(huge.Rsh(huge, 4)); err != nil || r != 6250000000000000000 {
+		t.Errorf("expected 6250000000000000000, got %d, %v", r, err)
+	}
+}
@@ -0,0 +1,195 @@
+package numbers
+
+import (
+	"fmt"
+	"strings"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/internal/lang"
+)
+
+// Speller spells out an integer in words in a language
+type Speller func(n int64) string
+
+var (
+	// Spellers maps a lower case language code to the function spell
+	// uses to write integers out in words, a Speller only has to
+	// handle the int64 range
+	Spellers = map[string]Speller{
+		"en": SpellEnglish,
+	}
+
+	romanNumerals = []struct {
+		value  int
+		symbol string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+		{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+		{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	}
+
+	englishOnes = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven",
+		"eight", "nine", "ten", "eleven", "twelve", "thirteen", "fourteen",
+		"fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	englishTens = []string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy",
+		"eighty", "ninety",
+	}
+	englishScales = []string{
+		"", "thousand", "million", "billion", "trillion", "quadrillion",
+		"quintillion",
+	}
+)
+
+// magnitude returns the absolute value of n as a uint64 so math.MinInt64
+// doesn't overflow
+func magnitude(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
+
+// OrdinalSuffix returns the English ordinal suffix for n, e.g. "st" for
+// 1, "nd" for 22 and "th" for 11
+func OrdinalSuffix(n int64) string {
+	m := magnitude(n)
+	if m%100 >= 11 && m%100 <= 13 {
+		return "th"
+	}
+	switch m % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+// Ordinal returns v with its English ordinal suffix, e.g. "2nd". v can
+// be anything Int64 accepts, fractions are truncated.
+func Ordinal(v interface{}) (string, error) {
+	n, err := checkInteger("ordinal", v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d%s", n, OrdinalSuffix(n)), nil
+}
+
+// Roman returns v as an upper case roman numeral, e.g. 14 is "XIV".
+// v must be between 1 and 3999.
+func Roman(v interface{}) (string, error) {
+	n, err := checkInteger("roman", v)
+	if err != nil {
+		return "", err
+	}
+	if n < 1 || n > 3999 {
+		return "", fmt.Errorf("roman: %d is out of range (1 to 3999)", n)
+	}
+	var sb strings.Builder
+	i := int(n)
+	for _, r := range romanNumerals {
+		for i >= r.value {
+			sb.WriteString(r.symbol)
+			i -= r.value
+		}
+	}
+	return sb.String(), nil
+}
+
+// ParseRoman returns the value of the roman numeral s, upper or lower
+// case. Numerals that aren't in standard form (e.g. "IIII" or "IC")
+// are an error.
+func ParseRoman(s string) (int, error) {
+	numeral := strings.ToUpper(strings.TrimSpace(s))
+	n, rest := 0, numeral
+	for _, r := range romanNumerals {
+		for strings.HasPrefix(rest, r.symbol) {
+			n += r.value
+			rest = rest[len(r.symbol):]
+		}
+	}
+	if n == 0 || rest != "" {
+		return 0, fmt.Errorf("parse_roman: %q is not a roman numeral", s)
+	}
+	// Only the standard form of n round trips, this rejects "IIII" and "VX"
+	if canonical, _ := Roman(n); canonical != numeral {
+		return 0, fmt.Errorf("parse_roman: %q is not a roman numeral", s)
+	}
+	return n, nil
+}
+
+// spellEnglishHundreds spells out n, which is less than 1000
+func spellEnglishHundreds(n uint64) string {
+	var words []string
+	if n >= 100 {
+		words = append(words, englishOnes[n/100], "hundred")
+		n = n % 100
+	}
+	switch {
+	case n >= 20 && n%10 != 0:
+		words = append(words, englishTens[n/10]+"-"+englishOnes[n%10])
+	case n >= 20:
+		words = append(words, englishTens[n/10])
+	case n > 0:
+		words = append(words, englishOnes[n])
+	}
+	return strings.Join(words, " ")
+}
+
+// SpellEnglish spells out n in (American) English, e.g. 121 is "one
+// hundred twenty-one" and -3 is "minus three"
+func SpellEnglish(n int64) string {
+	if n == 0 {
+		return englishOnes[0]
+	}
+	m := magnitude(n)
+	var groups []string
+	for scale := 0; m > 0; scale++ {
+		if group := m % 1000; group > 0 {
+			words := spellEnglishHundreds(group)
+			if englishScales[scale] != "" {
+				words += " " + englishScales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		m = m / 1000
+	}
+	if n < 0 {
+		groups = append([]string{"minus"}, groups...)
+	}
+	return strings.Join(groups, " ")
+}
+
+// LookupSpeller returns the Speller for a language code. Like
+// LookupNumberLocale region and encoding suffixes are ignored if there
+// is no exact match.
+func LookupSpeller(name string) (Speller, bool) {
+	for _, tag := range lang.Tags(name) {
+		if fn, ok := Spellers[tag]; ok {
+			return fn, true
+		}
+	}
+	return nil, false
+}
+
+// Spell spells out v in words in the language lang, an empty lang is
+// English. v can be anything Int64 accepts, fractions are truncated.
+func Spell(v interface{}, lang string) (string, error) {
+	n, err := checkInteger("spell", v)
+	if err != nil {
+		return "", err
+	}
+	if lang == "" {
+		lang = "en"
+	}
+	fn, ok := LookupSpeller(lang)
+	if ok == false {
+		return "", fmt.Errorf("spell: no speller for language %q", lang)
+	}
+	return fn(n), nil
+}
@@ -0,0 +1,125 @@
+package numbers
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestOrdinal(t *testing.T) {
+	testSet := []struct {
+		v        interface{}
+		expected string
+	}{
+		{1, "1st"},
+		{2, "2nd"},
+		{json.Number("3"), "3rd"},
+		{"4", "4th"},
+		{11, "11th"},
+		{12, "12th"},
+		{113, "113th"},
+		{21, "21st"},
+		{102, "102nd"},
+		{2.9, "2nd"},
+		{-1, "-1st"},
+		{0, "0th"},
+	}
+	for _, test := range testSet {
+		r, err := Ordinal(test.v)
+		if err != nil {
+			t.Errorf("%v, unexpected error %s", test.v, err)
+		} else if r != test.expected {
+			t.Errorf("%v, expected %q, got %q", test.v, test.expected, r)
+		}
+	}
+	if _, err := Ordinal("n/a"); err == nil {
+		t.Errorf("expected an error for n/a")
+	}
+}
+
+func TestRoman(t *testing.T) {
+	testSet := []struct {
+		n int
+		s string
+	}{
+		{1, "I"},
+		{4, "IV"},
+		{9, "IX"},
+		{14, "XIV"},
+		{40, "XL"},
+		{1994, "MCMXCIV"},
+		{2024, "MMXXIV"},
+		{3999, "MMMCMXCIX"},
+	}
+	for _, test := range testSet {
+		if r, err := Roman(test.n); err != nil || r != test.s {
+			t.Errorf("%d, expected %q, got %q, %v", test.n, test.s, r, err)
+		}
+		if r, err := ParseRoman(test.s); err != nil || r != test.n {
+			t.Errorf("%q, expected %d, got %d, %v", test.s, test.n, r, err)
+		}
+	}
+	if r, err := Roman(json.Number("7")); err != nil || r != "VII" {
+		t.Errorf("expected VII, got %q, %v", r, err)
+	}
+	if r, err := ParseRoman(" xiv "); err != nil || r != 14 {
+		t.Errorf("expected 14, got %d, %v", r, err)
+	}
+	for _, v := range []interface{}{0, -5, 4000, "n/a"} {
+		if r, err := Roman(v); err == nil {
+			t.Errorf("%v, expected an error, got %q", v, r)
+		}
+	}
+	for _, s := range []string{"", "IIII", "IC", "VX", "MMMM", "XIVX", "ABC"} {
+		if r, err := ParseRoman(s); err == nil {
+			t.Errorf("%q, expected an error, got %d", s, r)
+		}
+	}
+}
+
+func TestSpell(t *testing.T) {
+	testSet := []struct {
+		v        interface{}
+		expected string
+	}{
+		{0, "zero"},
+		{7, "seven"},
+		{13, "thirteen"},
+		{21, "twenty-one"},
+		{40, "forty"},
+		{100, "one hundred"},
+		{121, "one hundred twenty-one"},
+		{json.Number("1005"), "one thousand five"},
+		{"2,000,019", "two million nineteen"},
+		{-3, "minus three"},
+		{int64(math.MinInt64), "minus nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight"},
+	}
+	for _, test := range testSet {
+		r, err := Spell(test.v, "")
+		if err != nil {
+			t.Errorf("%v, unexpected error %s", test.v, err)
+		} else if r != test.expected {
+			t.Errorf("%v, expected %q, got %q", test.v, test.expected, r)
+		}
+	}
+	if r, err := Spell(5, "en-GB"); err != nil || r != "five" {
+		t.Errorf("expected five, got %q, %v", r, err)
+	}
+	if _, err := Spell(5, "xx"); err == nil {
+		t.Errorf("expected an error for an unknown language")
+	}
+	if _, err := Spell("n/a", "en"); err == nil {
+		t.Errorf("expected an error for n/a")
+	}
+	for _, v := range []interface{}{uint64(math.MaxUint64), 1e30, -1e19, math.Inf(1), math.NaN(), "18446744073709551615", json.Number("1e30")} {
+		if r, err := Spell(v, ""); err == nil {
+			t.Errorf("%v, expected an out of range error, got %q", v, r)
+		}
+		if r, err := Ordinal(v); err == nil {
+			t.Errorf("%v, expected an out of range error, got %q", v, r)
+		}
+	}
+	if r, err := Ordinal(uint64(math.MaxInt64)); err != nil || r != "9223372036854775807th" {
+		t.Errorf("expected 9223372036854775807th, got %q, %v", r, err)
+	}
+}
@@ -0,0 +1,157 @@
+package tmplfn
+
+import (
+	"fmt"
+	"time"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/internal/lang"
+)
+
+// RelativeStrings holds the phrases used to render relative times
+// (e.g. "3 days ago", "in 2 weeks") in a language.
+type RelativeStrings struct {
+	// Now is used when the difference is less than one unit of the
+	// granularity, e.g. "just now"
+	Now string
+	// Past and Future wrap the amount, e.g. "%s ago" and "in %s"
+	Past   string
+	Future string
+	// Units maps seconds, minutes, hours, days, weeks, months and
+	// years to the singular and plural forms of the amount,
+	// e.g. {"%d day", "%d days"}
+	Units map[string][2]string
+}
+
+var (
+	// RelativeLanguages holds the phrases for "3 days ago" and "in 2
+	// weeks" keyed by lower case language code, time_relative takes
+	// one of the keys (or a regional variant of it, see
+	// LookupRelativeStrings)
+	RelativeLanguages = map[string]*RelativeStrings{
+		"en": {
+			Now:    "just now",
+			Past:   "%s ago",
+			Future: "in %s",
+			Units: map[string][2]string{
+				"seconds": {"%d second", "%d seconds"},
+				"minutes": {"%d minute", "%d minutes"},
+				"hours":   {"%d hour", "%d hours"},
+				"days":    {"%d day", "%d days"},
+				"weeks":   {"%d week", "%d weeks"},
+				"months":  {"%d month", "%d months"},
+				"years":   {"%d year", "%d years"},
+			},
+		},
+		"es": {
+			Now:    "ahora mismo",
+			Past:   "hace %s",
+			Future: "dentro de %s",
+			Units: map[string][2]string{
+				"seconds": {"%d segundo", "%d segundos"},
+				"minutes": {"%d minuto", "%d minutos"},
+				"hours":   {"%d hora", "%d horas"},
+				"days":    {"%d día", "%d días"},
+				"weeks":   {"%d semana", "%d semanas"},
+				"months":  {"%d mes", "%d meses"},
+				"years":   {"%d año", "%d años"},
+			},
+		},
+		"fr": {
+			Now:    "à l'instant",
+			Past:   "il y a %s",
+			Future: "dans %s",
+			Units: map[string][2]string{
+				"seconds": {"%d seconde", "%d secondes"},
+				"minutes": {"%d minute", "%d minutes"},
+				"hours":   {"%d heure", "%d heures"},
+				"days":    {"%d jour", "%d jours"},
+				"weeks":   {"%d semaine", "%d semaines"},
+				"months":  {"%d mois", "%d mois"},
+				"years":   {"%d an", "%d ans"},
+			},
+		},
+		"de": {
+			Now:    "gerade eben",
+			Past:   "vor %s",
+			Future: "in %s",
+			Units: map[string][2]string{
+				"seconds": {"%d Sekunde", "%d Sekunden"},
+				"minutes": {"%d Minute", "%d Minuten"},
+				"hours":   {"%d Stunde", "%d Stunden"},
+				"days":    {"%d Tag", "%d Tagen"},
+				"weeks":   {"%d Woche", "%d Wochen"},
+				"months":  {"%d Monat", "%d Monaten"},
+				"years":   {"%d Jahr", "%d Jahren"},
+			},
+		},
+		"zh": {
+			Now:    "刚刚",
+			Past:   "%s前",
+			Future: "%s后",
+			Units: map[string][2]string{
+				"seconds": {"%d秒", "%d秒"},
+				"minutes": {"%d分钟", "%d分钟"},
+				"hours":   {"%d小时", "%d小时"},
+				"days":    {"%d天", "%d天"},
+				"weeks":   {"%d周", "%d周"},
+				"months":  {"%d个月", "%d个月"},
+				"years":   {"%d年", "%d年"},
+			},
+		},
+	}
+
+	// relativeUnits are the units used by HumanizeRelative from largest
+	// to smallest
+	relativeUnits = []string{"years", "months", "weeks", "days", "hours", "minutes", "seconds"}
+)
+
+// LookupRelativeStrings returns the RelativeStrings for a language
+// code, like LookupLocale "pt-BR" finds "pt" if there is no exact match
+func LookupRelativeStrings(name string) (*RelativeStrings, bool) {
+	for _, tag := range lang.Tags(name) {
+		if rs, ok := RelativeLanguages[tag]; ok {
+			return rs, true
+		}
+	}
+	return nil, false
+}
+
+// HumanizeRelative describes t relative to ref using the largest whole
+// unit, e.g. "3 days ago" or "in 2 weeks". Granularity is the smallest
+// unit reported (e.g. "days"), differences smaller than one of it are
+// rendered as rs.Now. An empty granularity is "seconds" and a nil rs
+// is English.
+func HumanizeRelative(t, ref time.Time, granularity string, rs *RelativeStrings) string {
+	if rs == nil {
+		rs = RelativeLanguages["en"]
+	}
+	smallest := normalizeUnit(granularity)
+	if smallest == "" || smallest == "nanoseconds" || smallest == "milliseconds" {
+		smallest = "seconds"
+	}
+	if smallest == "quarters" {
+		smallest = "months"
+	}
+	phrase := rs.Past
+	start, end := t, ref
+	if t.After(ref) {
+		phrase = rs.Future
+		start, end = ref, t
+	}
+	for _, unit := range relativeUnits {
+		n, _ := dateDiff(start, end, unit)
+		if n > 0 {
+			forms := rs.Units[unit]
+			amount := fmt.Sprintf(forms[1], n)
+			if n == 1 {
+				amount = fmt.Sprintf(forms[0], n)
+			}
+			return fmt.Sprintf(phrase, amount)
+		}
+		if unit == smallest {
+			break
+		}
+	}
+	return rs.Now
+}
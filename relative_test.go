@@ -0,0 +1,47 @@
+package tmplfn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanizeRelative(t *testing.T) {
+	ref := time.Date(2017, 3, 16, 14, 0, 0, 0, time.UTC)
+	testSet := []struct {
+		t           time.Time
+		granularity string
+		lang        string
+		expected    string
+	}{
+		{ref.Add(-3 * 24 * time.Hour), "", "en", "3 days ago"},
+		{ref.Add(-1 * time.Hour), "", "en", "1 hour ago"},
+		{ref.Add(14 * 24 * time.Hour), "", "en", "in 2 weeks"},
+		{ref.Add(-30 * time.Second), "minutes", "en", "just now"},
+		{ref.Add(-5 * time.Hour), "days", "en", "just now"},
+		{ref.AddDate(-2, 0, 0), "", "en", "2 years ago"},
+		{ref.AddDate(0, -2, 0), "", "es", "hace 2 meses"},
+		{ref.Add(3 * time.Hour), "", "de", "in 3 Stunden"},
+		{ref.Add(-3 * 24 * time.Hour), "", "zh", "3天前"},
+	}
+	for _, test := range testSet {
+		r := HumanizeRelative(test.t, ref, test.granularity, RelativeLanguages[test.lang])
+		if r != test.expected {
+			t.Errorf("%s, expected %q, got %q", test.t, test.expected, r)
+		}
+	}
+
+	timeAgoFrom := Time["time_ago_from"].(func(interface{}, interface{}) string)
+	if r := timeAgoFrom("2017-03-13T14:00:00Z", "2017-03-16T14:00:00Z"); r != "3 days ago" {
+		t.Errorf("expected %q, got %q", "3 days ago", r)
+	}
+	timeRelative := Time["time_relative"].(func(interface{}, interface{}, string, string) string)
+	if r := timeRelative("2017-03-20", "2017-03-16", "days", "fr"); r != "dans 4 jours" {
+		t.Errorf("expected %q, got %q", "dans 4 jours", r)
+	}
+	if r := timeRelative("2017-03-20", "2017-03-16", "days", "fr_CA.UTF-8"); r != "dans 4 jours" {
+		t.Errorf("expected %q, got %q", "dans 4 jours", r)
+	}
+	if r := timeRelative("2017-03-20", "2017-03-16", "days", "tlh"); r != "" {
+		t.Errorf("expected an empty string for an unknown language, got %q", r)
+	}
+}
@@ -0,0 +1,74 @@
+package tmplfn
+
+import (
+	"fmt"
+	"text/template"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/numbers"
+	"github.com/caltechlibrary/tmplfn/numbers/stats"
+)
+
+var (
+	// Stats provides descriptive statistics and histograms over lists.
+	// Like the Math aggregates each function takes an optional dot path
+	// to pluck from each element (e.g. stddev .records ".downloads").
+	Stats = template.FuncMap{
+		"variance": func(list interface{}, p ...string) float64 {
+			return stats.Variance(pluck(list, p))
+		},
+		"sample_variance": func(list interface{}, p ...string) float64 {
+			return stats.SampleVariance(pluck(list, p))
+		},
+		"stddev": func(list interface{}, p ...string) float64 {
+			return stats.StdDev(pluck(list, p))
+		},
+		"sample_stddev": func(list interface{}, p ...string) float64 {
+			return stats.SampleStdDev(pluck(list, p))
+		},
+		// quantile takes a fraction between 0 and 1 (e.g. 0.9)
+		"quantile": func(list interface{}, q interface{}, p ...string) float64 {
+			return stats.Quantile(pluck(list, p), numbers.Float64(q))
+		},
+		// percentile takes a value between 0 and 100 (e.g. 95)
+		"percentile": func(list interface{}, pct interface{}, p ...string) float64 {
+			return stats.Percentile(pluck(list, p), numbers.Float64(pct))
+		},
+		// quantiles returns the cut points dividing the list into n groups,
+		// e.g. quantiles .scores 4 returns the quartiles. n over
+		// stats.MaxBins is an error, as it is for the histograms.
+		"quantiles": func(list interface{}, n interface{}, p ...string) ([]float64, error) {
+			i, err := binCount("quantiles", n)
+			if err != nil {
+				return nil, err
+			}
+			return stats.Quantiles(pluck(list, p), i), nil
+		},
+		// histogram returns n bins of equal width, each with .Lower, .Upper and .Count
+		"histogram": func(list interface{}, n interface{}, p ...string) ([]stats.Bin, error) {
+			i, err := binCount("histogram", n)
+			if err != nil {
+				return nil, err
+			}
+			return stats.Histogram(pluck(list, p), i), nil
+		},
+		// quantile_histogram returns n bins holding about the same number of values
+		"quantile_histogram": func(list interface{}, n interface{}, p ...string) ([]stats.Bin, error) {
+			i, err := binCount("quantile_histogram", n)
+			if err != nil {
+				return nil, err
+			}
+			return stats.QuantileHistogram(pluck(list, p), i), nil
+		},
+	}
+)
+
+// binCount returns n as an int or an error naming op if it isn't a
+// number or is more than stats.MaxBins
+func binCount(op string, n interface{}) (int, error) {
+	i, err := numbers.IntErr(n)
+	if err != nil || i > stats.MaxBins {
+		return 0, fmt.Errorf("%s: %v isn't a number of bins up to %d", op, n, stats.MaxBins)
+	}
+	return i, nil
+}
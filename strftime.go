@@ -0,0 +1,283 @@
+package tmplfn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// strftimeLayouts maps strftime directives to Go layout elements,
+	// directives missing here have no Go layout equivalent
+	strftimeLayouts = map[string]string{
+		"a":  "Mon",
+		"A":  "Monday",
+		"b":  "Jan",
+		"h":  "Jan",
+		"B":  "January",
+		"c":  "Mon Jan _2 15:04:05 2006",
+		"d":  "02",
+		"D":  "01/02/06",
+		"e":  "_2",
+		"F":  "2006-01-02",
+		"H":  "15",
+		"I":  "03",
+		"m":  "01",
+		"M":  "04",
+		"p":  "PM",
+		"P":  "pm",
+		"r":  "03:04:05 PM",
+		"R":  "15:04",
+		"S":  "05",
+		"T":  "15:04:05",
+		"x":  "01/02/06",
+		"X":  "15:04:05",
+		"y":  "06",
+		"Y":  "2006",
+		"z":  "-0700",
+		":z": "-07:00",
+		"Z":  "MST",
+		"n":  "\n",
+		"t":  "\t",
+		"%":  "%",
+	}
+
+	// layoutProbes are two times differing in every field, a string
+	// that formats to itself with both contains no layout elements
+	layoutProbes = []time.Time{
+		time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC),
+		time.Date(2017, 11, 26, 19, 48, 57, 0, time.FixedZone("XYZ", 3600)),
+	}
+)
+
+// Strftime formats t using a strftime(3) style format. The POSIX
+// directives are supported along with these extensions,
+//
+//	%q quarter of the year (1..4)
+//	%f microseconds (000000..999999)
+//	%L milliseconds (000..999)
+//	%N nanoseconds (000000000..999999999)
+//	%:z zone offset with a colon (e.g. -07:00)
+//	%s seconds since the Unix epoch
+//	%k, %l hour (24 and 12 hour clock) padded with a space
+//
+// Numeric directives take the GNU flags "-" (no padding), "_" (pad with
+// spaces) and "0" (pad with zeros), e.g. %-d. Unknown directives are
+// copied to the output as is.
+func Strftime(t time.Time, format string) string {
+	return StrftimeLocale(t, format, nil)
+}
+
+// StrftimeLocale works like Strftime with month and weekday names
+// (%a, %A, %b, %B) taken from l. A nil l is English.
+func StrftimeLocale(t time.Time, format string, l *Locale) string {
+	if l == nil {
+		l = Locales["en"]
+	}
+	var out strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i == len(format)-1 {
+			out.WriteByte(format[i])
+			continue
+		}
+		start := i
+		i++
+		flag := byte(0)
+		if strings.IndexByte("-_0", format[i]) >= 0 && i < len(format)-1 {
+			flag = format[i]
+			i++
+		}
+		directive := format[i : i+1]
+		if directive == ":" && i < len(format)-1 && format[i+1] == 'z' {
+			directive = ":z"
+			i++
+		}
+		value, width, pad, ok := strftimeDirective(t, directive, l)
+		if ok == false {
+			out.WriteString(format[start : i+1])
+			continue
+		}
+		if width > 0 {
+			switch flag {
+			case '-':
+				pad = 0
+			case '_':
+				pad = ' '
+			case '0':
+				pad = '0'
+			}
+			if pad != 0 {
+				value = padLeft(value, width, pad)
+			}
+		}
+		out.WriteString(value)
+	}
+	return out.String()
+}
+
+// strftimeDirective returns the unpadded value of a directive, the
+// width and pad character of numeric values (width is zero for text)
+func strftimeDirective(t time.Time, directive string, l *Locale) (string, int, byte, bool) {
+	num := func(n, width int, pad byte) (string, int, byte, bool) {
+		return strconv.Itoa(n), width, pad, true
+	}
+	text := func(s string) (string, int, byte, bool) {
+		return s, 0, 0, true
+	}
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	isoYear, isoWeek := t.ISOWeek()
+	switch directive {
+	case "a":
+		return text(l.ShortWeekdays[t.Weekday()])
+	case "A":
+		return text(l.Weekdays[t.Weekday()])
+	case "b", "h":
+		return text(l.ShortMonths[t.Month()-1])
+	case "B":
+		return text(l.Months[t.Month()-1])
+	case "C":
+		return num(t.Year()/100, 2, '0')
+	case "d":
+		return num(t.Day(), 2, '0')
+	case "e":
+		return num(t.Day(), 2, ' ')
+	case "f":
+		return num(t.Nanosecond()/1000, 6, '0')
+	case "G":
+		return num(isoYear, 4, '0')
+	case "g":
+		return num(isoYear%100, 2, '0')
+	case "H":
+		return num(t.Hour(), 2, '0')
+	case "I":
+		return num(hour12, 2, '0')
+	case "j":
+		return num(t.YearDay(), 3, '0')
+	case "k":
+		return num(t.Hour(), 2, ' ')
+	case "l":
+		return num(hour12, 2, ' ')
+	case "L":
+		return num(t.Nanosecond()/1000000, 3, '0')
+	case "m":
+		return num(int(t.Month()), 2, '0')
+	case "M":
+		return num(t.Minute(), 2, '0')
+	case "N":
+		return num(t.Nanosecond(), 9, '0')
+	case "p":
+		return text(t.Format("PM"))
+	case "P":
+		return text(t.Format("pm"))
+	case "q":
+		return num(Quarter(t), 1, '0')
+	case "s":
+		return text(strconv.FormatInt(t.Unix(), 10))
+	case "S":
+		return num(t.Second(), 2, '0')
+	case "u":
+		return num((int(t.Weekday())+6)%7+1, 1, '0')
+	case "U":
+		return num((t.YearDay()+6-int(t.Weekday()))/7, 2, '0')
+	case "V":
+		return num(isoWeek, 2, '0')
+	case "w":
+		return num(int(t.Weekday()), 1, '0')
+	case "W":
+		return num((t.YearDay()+6-(int(t.Weekday())+6)%7)/7, 2, '0')
+	case "y":
+		return num(t.Year()%100, 2, '0')
+	case "Y":
+		return num(t.Year(), 4, '0')
+	case "c":
+		return text(StrftimeLocale(t, "%a %b %e %H:%M:%S %Y", l))
+	case "D", "x":
+		return text(t.Format("01/02/06"))
+	case "F":
+		return text(t.Format("2006-01-02"))
+	case "r":
+		return text(t.Format("03:04:05 PM"))
+	case "R":
+		return text(t.Format("15:04"))
+	case "T", "X":
+		return text(t.Format("15:04:05"))
+	case "z":
+		return text(t.Format("-0700"))
+	case ":z":
+		return text(t.Format("-07:00"))
+	case "Z":
+		return text(t.Format("MST"))
+	case "n":
+		return text("\n")
+	case "t":
+		return text("\t")
+	case "%":
+		return text("%")
+	}
+	return "", 0, 0, false
+}
+
+// padLeft pads s to width with pad
+func padLeft(s string, width int, pad byte) string {
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat(string(pad), width-len(s)) + s
+}
+
+// StrftimeToLayout translates a strftime format into a Go layout for
+// use with time.Format and time.Parse. Directives without a Go layout
+// equivalent (e.g. %j, %s, %q) and literal text that Go would read as
+// a layout element (e.g. "Jan" or a digit) return an error.
+func StrftimeToLayout(format string) (string, error) {
+	var (
+		out     strings.Builder
+		literal strings.Builder
+	)
+	flush := func() error {
+		lit := literal.String()
+		literal.Reset()
+		for _, t := range layoutProbes {
+			if t.Format(lit) != lit {
+				return fmt.Errorf("literal %q can't be expressed in a Go layout", lit)
+			}
+		}
+		out.WriteString(lit)
+		return nil
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			literal.WriteByte(format[i])
+			continue
+		}
+		if i == len(format)-1 {
+			return "", fmt.Errorf("format ends with %%")
+		}
+		i++
+		directive := format[i : i+1]
+		if directive == ":" && i < len(format)-1 && format[i+1] == 'z' {
+			directive = ":z"
+			i++
+		}
+		layout, ok := strftimeLayouts[directive]
+		if ok == false {
+			return "", fmt.Errorf("%%%s has no Go layout equivalent", directive)
+		}
+		if directive == "%" || directive == "n" || directive == "t" {
+			literal.WriteString(layout)
+			continue
+		}
+		if err := flush(); err != nil {
+			return "", err
+		}
+		out.WriteString(layout)
+	}
+	if err := flush(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
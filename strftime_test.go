@@ -0,0 +1,60 @@
+package tmplfn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStrftime(t *testing.T) {
+	dt := time.Date(2017, 3, 5, 14, 2, 5, 123456789, time.FixedZone("PDT", -7*3600))
+	testSet := map[string]string{
+		"%Y-%m-%d %H:%M": "2017-03-05 14:02",
+		"%a %A %b %B":    "Sun Sunday Mar March",
+		"%-d/%-m/%y":     "5/3/17",
+		"%e|%k|%l|%I %p": " 5|14| 2|02 PM",
+		"%j %U %W %V %G": "064 10 09 09 2017",
+		"%u %w %q %C":    "7 0 1 20",
+		"%f %L %N":       "123456 123 123456789",
+		"%z %:z %Z":      "-0700 -07:00 PDT",
+		"%F %T %%":       "2017-03-05 14:02:05 %",
+		"%s":             "1488747725",
+		"100%% done %Q":  "100% done %Q",
+	}
+	for format, expected := range testSet {
+		if r := Strftime(dt, format); r != expected {
+			t.Errorf("%q, expected %q, got %q", format, expected, r)
+		}
+	}
+
+	l, _ := LookupLocale("es")
+	if r := StrftimeLocale(dt, "%A %-d de %B", l); r != "domingo 5 de marzo" {
+		t.Errorf("expected %q, got %q", "domingo 5 de marzo", r)
+	}
+}
+
+func TestStrftimeToLayout(t *testing.T) {
+	testSet := map[string]string{
+		"%Y-%m-%d %H:%M:%S": "2006-01-02 15:04:05",
+		"%B %e, %Y":         "January _2, 2006",
+		"%d/%m/%y %I%p %:z": "02/01/06 03PM -07:00",
+		"at %H:%M %%":       "at 15:04 %",
+	}
+	for format, expected := range testSet {
+		r, err := StrftimeToLayout(format)
+		if err != nil {
+			t.Errorf("%q, unexpected error %s", format, err)
+		} else if r != expected {
+			t.Errorf("%q, expected %q, got %q", format, expected, r)
+		}
+	}
+	for _, format := range []string{"%j", "%q", "Day 1 %d", "%Y on Monday", "%"} {
+		if r, err := StrftimeToLayout(format); err == nil {
+			t.Errorf("%q, expected an error, got %q", format, r)
+		}
+	}
+
+	datefmt := Time["datefmt"].(func(string, string, string, string) string)
+	if r := datefmt("2017-03", "%B %-d, %Y", "%B %Y", "%Y"); r != "March 2017" {
+		t.Errorf("expected %q, got %q", "March 2017", r)
+	}
+}
@@ -0,0 +1,578 @@
+package tmplfn
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/numbers"
+
+	// Embed the IANA time zone database so zone names resolve on
+	// systems without one installed.
+	_ "time/tzdata"
+)
+
+// TimeOptions holds the settings used by TimeFuncMap to build a set
+// of Time functions.
+type TimeOptions struct {
+	// Location is the default time zone. Dates without a zone are read
+	// in it and every result is rendered in it. If nil dates without a
+	// zone are read as UTC and results keep the zone they were parsed
+	// in ("now" uses the local zone).
+	Location *time.Location
+
+	// Locale is the language code (e.g. "es", see Locales) used by
+	// localdate, timefmt_locale, month_name and weekday_name when a call
+	// doesn't name one, empty is "en"
+	Locale string
+
+	// FiscalYearStart is the first month of the fiscal year used by
+	// fiscal_year and fiscal_year_label, zero is DefaultFiscalYearStart
+	FiscalYearStart time.Month
+
+	// AcademicYearStart is the first month of the academic year used by
+	// academic_year and academic_year_label, zero is
+	// DefaultAcademicYearStart
+	AcademicYearStart time.Month
+
+	// Clock supplies the time used for "now", nil is SystemClock
+	Clock Clock
+
+	// Granularity is the smallest unit (e.g. "minutes", "days") used
+	// by time_ago and time_until, empty is "seconds"
+	Granularity string
+
+	// Relative holds the phrases used by time_ago and time_until, nil
+	// is English (see RelativeLanguages)
+	Relative *RelativeStrings
+}
+
+// defaultTimeFuncs reads dates for functions outside the Time FuncMap
+var defaultTimeFuncs = &timeFuncs{clock: SystemClock, fiscal: DefaultFiscalYearStart, academic: DefaultAcademicYearStart}
+
+// timeFuncs holds the options the Time functions are built around
+type timeFuncs struct {
+	loc         *time.Location
+	clock       Clock
+	locale      string
+	fiscal      time.Month
+	academic    time.Month
+	granularity string
+	relative    *RelativeStrings
+}
+
+// TimeFuncMap returns the Time functions configured by opts, a nil opts
+// returns the same functions as Time. E.g. render every date in
+// Los Angeles time,
+//
+//	loc, _ := time.LoadLocation("America/Los_Angeles")
+//	fm := tmplfn.Join(tmplfn.AllFuncs(), tmplfn.TimeFuncMap(&tmplfn.TimeOptions{Location: loc}))
+func TimeFuncMap(opts *TimeOptions) template.FuncMap {
+	tf := &timeFuncs{clock: SystemClock, fiscal: DefaultFiscalYearStart, academic: DefaultAcademicYearStart}
+	if opts != nil {
+		tf.loc = opts.Location
+		if opts.Clock != nil {
+			tf.clock = opts.Clock
+		}
+		tf.locale = opts.Locale
+		if opts.FiscalYearStart != 0 {
+			tf.fiscal = opts.FiscalYearStart
+		}
+		if opts.AcademicYearStart != 0 {
+			tf.academic = opts.AcademicYearStart
+		}
+		tf.granularity = opts.Granularity
+		tf.relative = opts.Relative
+	}
+	// timeFromNow is both time_ago and time_until, the direction comes
+	// from v so either name reads naturally in a template
+	timeFromNow := func(v interface{}) string {
+		return tf.relativeTo(v, "now", tf.granularity, "")
+	}
+	return Join(tf.calendarFuncs(), template.FuncMap{
+		"year": func(v interface{}) string {
+			return tf.format(v, "2006")
+		},
+		// timefmt normalizes and formates a datetime, v, using fmt
+		"timefmt": func(v interface{}, fmt string) string {
+			return tf.format(v, fmt)
+		},
+		"rfc3339": func(v interface{}) string {
+			return tf.format(v, time.RFC3339)
+		},
+		"rfc1123": func(v interface{}) string {
+			return tf.format(v, time.RFC1123)
+		},
+		"rfc1123z": func(v interface{}) string {
+			return tf.format(v, time.RFC1123Z)
+		},
+		"rfc822z": func(v interface{}) string {
+			return tf.format(v, time.RFC822Z)
+		},
+		"rfc822": func(v interface{}) string {
+			return tf.format(v, time.RFC822)
+		},
+		// datefmt FIXME: this is ugly, depreciate in favor of timefmt or rename to something appropriate
+		// Output formats containing a "%" are read as strftime formats.
+		"datefmt": func(dt, outputFmtYMD, outputFmtYM, outputFmtY string) string {
+			var (
+				inputFmt  string
+				outputFmt string
+			)
+			// NOTE: Date input formats can be YYYY, YYYY-MM and YYYY,
+			// we need to define output formats for each
+			switch {
+			case len(dt) == 4:
+				inputFmt = "2006"
+				outputFmt = outputFmtY
+			case len(dt) > 4 && len(dt) <= 7:
+				inputFmt = "2006-01"
+				outputFmt = outputFmtYM
+			default:
+				// Full dates and datetimes go through ParseDateTime
+				d, err := tf.toTime(dt)
+				if err != nil {
+					return fmt.Sprintf("%s, %s", dt, err.Error())
+				}
+				return formatLayoutOrStrftime(d, outputFmtYMD)
+			}
+			//intputFmt: 2006-01-02
+			//outputfmt: Jan _2, 2006
+			d, err := time.Parse(inputFmt, dt)
+			if err != nil {
+				return fmt.Sprintf("%s, %s", dt, err.Error())
+			}
+			return formatLayoutOrStrftime(d, outputFmt)
+		},
+		// strftime formats v with a strftime style format (e.g. "%Y-%m-%d %H:%M"), see Strftime.
+		// Month and weekday names use the locale named or the FuncMap's locale.
+		"strftime": func(v interface{}, format string, locale ...string) string {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return ""
+			}
+			l, ok := tf.lookupLocale(locale)
+			if ok == false {
+				return ""
+			}
+			return StrftimeLocale(dt, format, l)
+		},
+		// iso_week returns the ISO 8601 week number (1 to 53) of v
+		"iso_week": func(v interface{}) int {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return 0
+			}
+			_, week := dt.ISOWeek()
+			return week
+		},
+		// iso_year returns the ISO 8601 week numbering year of v
+		"iso_year": func(v interface{}) int {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return 0
+			}
+			year, _ := dt.ISOWeek()
+			return year
+		},
+		// quarter returns the calendar quarter (1 to 4) of v
+		"quarter": func(v interface{}) int {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return 0
+			}
+			return Quarter(dt)
+		},
+		// day_of_year returns the day of the year (1 to 366) of v
+		"day_of_year": func(v interface{}) int {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return 0
+			}
+			return dt.YearDay()
+		},
+		// fiscal_year returns the fiscal year (named by the year it ends in) of v,
+		// an optional month number overrides the FuncMap's fiscal year start
+		"fiscal_year": func(v interface{}, start ...int) int {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return 0
+			}
+			return FiscalYear(dt, tf.startMonth(tf.fiscal, start))
+		},
+		// fiscal_year_label returns the fiscal year of v as a label, e.g. "FY2024"
+		"fiscal_year_label": func(v interface{}, start ...int) string {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return ""
+			}
+			return fmt.Sprintf("FY%d", FiscalYear(dt, tf.startMonth(tf.fiscal, start)))
+		},
+		// academic_year returns the year the academic year containing v began in,
+		// an optional month number overrides the FuncMap's academic year start
+		"academic_year": func(v interface{}, start ...int) int {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return 0
+			}
+			return AcademicYear(dt, tf.startMonth(tf.academic, start))
+		},
+		// academic_year_label returns the academic year of v as a label, e.g. "2023–24"
+		"academic_year_label": func(v interface{}, start ...int) string {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return ""
+			}
+			month := tf.startMonth(tf.academic, start)
+			return AcademicYearLabel(AcademicYear(dt, month), month)
+		},
+		// duration_human renders a duration (e.g. "PT36H", "90m" or seconds) as "1 day 12 hours"
+		"duration_human": func(v interface{}) string {
+			d, err := ParseDuration(v)
+			if err != nil {
+				return ""
+			}
+			return HumanizeDuration(d)
+		},
+		// duration_clock renders a duration as hours, minutes and seconds, e.g. "36:00:00"
+		"duration_clock": func(v interface{}) string {
+			d, err := ParseDuration(v)
+			if err != nil {
+				return ""
+			}
+			return ClockDuration(d)
+		},
+		// duration_round renders a duration rounded to its largest unit, e.g. "2 days"
+		"duration_round": func(v interface{}) string {
+			d, err := ParseDuration(v)
+			if err != nil {
+				return ""
+			}
+			return RoundDuration(d)
+		},
+		// duration_seconds returns a duration in seconds
+		"duration_seconds": func(v interface{}) float64 {
+			d, err := ParseDuration(v)
+			if err != nil {
+				return 0
+			}
+			return d.Seconds()
+		},
+		// in_zone converts v to the IANA time zone name (e.g. "America/Los_Angeles"),
+		// the result can be passed to any other Time function
+		"in_zone": func(name string, v interface{}) (time.Time, error) {
+			loc, err := time.LoadLocation(name)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("in_zone: %s", err)
+			}
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("in_zone: %s", err)
+			}
+			return dt.In(loc), nil
+		},
+		// zone_abbr returns the zone abbreviation (e.g. PST) of v
+		"zone_abbr": func(v interface{}) string {
+			return tf.format(v, "MST")
+		},
+		// zone_offset returns the zone offset (e.g. -08:00) of v
+		"zone_offset": func(v interface{}) string {
+			return tf.format(v, "-07:00")
+		},
+		// date_add adds amount of unit (e.g. 30 "days") to v, units are nanoseconds,
+		// milliseconds, seconds, minutes, hours, days, weeks, months, quarters and years.
+		// Adding months keeps the day within the month (e.g. Jan 31 plus a month is Feb 28).
+		"date_add": func(amount interface{}, unit string, v interface{}) (time.Time, error) {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("date_add: %s", err)
+			}
+			if dt, err = dateAdd(dt, numbers.Int(amount), unit); err != nil {
+				return time.Time{}, fmt.Errorf("date_add: %s", err)
+			}
+			return dt, nil
+		},
+		// date_sub subtracts amount of unit from v
+		"date_sub": func(amount interface{}, unit string, v interface{}) (time.Time, error) {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("date_sub: %s", err)
+			}
+			if dt, err = dateAdd(dt, -numbers.Int(amount), unit); err != nil {
+				return time.Time{}, fmt.Errorf("date_sub: %s", err)
+			}
+			return dt, nil
+		},
+		// date_add_duration adds a duration (e.g. "36h", "PT36H", see ParseDuration) to v
+		"date_add_duration": func(duration interface{}, v interface{}) (time.Time, error) {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("date_add_duration: %s", err)
+			}
+			d, err := ParseDuration(duration)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("date_add_duration: %s", err)
+			}
+			return dt.Add(d), nil
+		},
+		// date_diff returns the whole number of units from start to end,
+		// negative if end is before start
+		"date_diff": func(start, end interface{}, unit string) (int, error) {
+			s, err := tf.toTime(start)
+			if err != nil {
+				return 0, fmt.Errorf("date_diff: %s", err)
+			}
+			e, err := tf.toTime(end)
+			if err != nil {
+				return 0, fmt.Errorf("date_diff: %s", err)
+			}
+			n, err := dateDiff(s, e, unit)
+			if err != nil {
+				return 0, fmt.Errorf("date_diff: %s", err)
+			}
+			return n, nil
+		},
+		// date_before returns true if a is before b
+		"date_before": func(a, b interface{}) bool {
+			t1, t2, ok := tf.toTimes(a, b)
+			return ok && t1.Before(t2)
+		},
+		// date_after returns true if a is after b
+		"date_after": func(a, b interface{}) bool {
+			t1, t2, ok := tf.toTimes(a, b)
+			return ok && t1.After(t2)
+		},
+		// date_equal returns true if a and b are the same instant
+		"date_equal": func(a, b interface{}) bool {
+			t1, t2, ok := tf.toTimes(a, b)
+			return ok && t1.Equal(t2)
+		},
+		// date_between returns true if v falls between start and end inclusive
+		"date_between": func(v, start, end interface{}) bool {
+			t, s, ok := tf.toTimes(v, start)
+			if ok == false {
+				return false
+			}
+			e, err := tf.toTime(end)
+			if err != nil {
+				return false
+			}
+			return t.Before(s) == false && t.After(e) == false
+		},
+		// start_of returns the start of the unit (e.g. "month") containing v,
+		// weeks start on Monday
+		"start_of": func(unit string, v interface{}) (time.Time, error) {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("start_of: %s", err)
+			}
+			if dt, err = startOf(dt, unit); err != nil {
+				return time.Time{}, fmt.Errorf("start_of: %s", err)
+			}
+			return dt, nil
+		},
+		// end_of returns the last instant of the unit (e.g. "year") containing v
+		"end_of": func(unit string, v interface{}) (time.Time, error) {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("end_of: %s", err)
+			}
+			if dt, err = endOf(dt, unit); err != nil {
+				return time.Time{}, fmt.Errorf("end_of: %s", err)
+			}
+			return dt, nil
+		},
+		// localdate formats v in a locale's style ("full", "long", "medium" or "short"),
+		// e.g. localdate .pub_date "long" "es" renders "16 de marzo de 2017"
+		"localdate": func(v interface{}, style string, locale ...string) string {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return ""
+			}
+			l, ok := tf.lookupLocale(locale)
+			if ok == false {
+				return ""
+			}
+			return FormatLocaleStyle(dt, style, l)
+		},
+		// timefmt_locale works like timefmt with month and weekday names in a locale
+		"timefmt_locale": func(v interface{}, layout string, locale ...string) string {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return ""
+			}
+			l, ok := tf.lookupLocale(locale)
+			if ok == false {
+				return ""
+			}
+			return FormatLocale(dt, layout, l)
+		},
+		// month_name returns the month name of v in a locale
+		"month_name": func(v interface{}, locale ...string) string {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return ""
+			}
+			l, ok := tf.lookupLocale(locale)
+			if ok == false {
+				return ""
+			}
+			return l.Months[dt.Month()-1]
+		},
+		// weekday_name returns the weekday name of v in a locale
+		"weekday_name": func(v interface{}, locale ...string) string {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return ""
+			}
+			l, ok := tf.lookupLocale(locale)
+			if ok == false {
+				return ""
+			}
+			return l.Weekdays[dt.Weekday()]
+		},
+		// time_ago describes v relative to now, e.g. "3 days ago" ("in 3 days" if v is in the future)
+		"time_ago": timeFromNow,
+		// time_until is time_ago, e.g. "in 2 weeks" ("2 weeks ago" if v is in the past)
+		"time_until": timeFromNow,
+		// time_ago_from describes v relative to ref instead of now
+		"time_ago_from": func(v, ref interface{}) string {
+			return tf.relativeTo(v, ref, tf.granularity, "")
+		},
+		// time_relative describes v relative to ref with the smallest unit
+		// granularity (e.g. "days") in language lang (e.g. "es", see RelativeLanguages)
+		"time_relative": func(v, ref interface{}, granularity string, lang string) string {
+			return tf.relativeTo(v, ref, granularity, lang)
+		},
+		// zone_name returns the name of the zone (e.g. America/Los_Angeles) of v
+		"zone_name": func(v interface{}) string {
+			dt, err := tf.toTime(v)
+			if err != nil {
+				return ""
+			}
+			return dt.Location().String()
+		},
+	})
+}
+
+// formatLayoutOrStrftime formats t with a strftime format if it
+// contains a "%" otherwise as a Go layout
+func formatLayoutOrStrftime(t time.Time, format string) string {
+	if strings.Contains(format, "%") {
+		return Strftime(t, format)
+	}
+	return t.Format(format)
+}
+
+// toTime converts v to a time.Time. Strings (and numbers) are parsed
+// with ParseDateTime, "now" is the time from the FuncMap's Clock. These
+// are rendered in the FuncMap's Location, a time.Time keeps its own
+// location (e.g. from in_zone).
+func (tf *timeFuncs) toTime(v interface{}) (time.Time, error) {
+	var (
+		dt  time.Time
+		err error
+	)
+	loc := tf.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	switch x := v.(type) {
+	case time.Time:
+		return x, nil
+	case *time.Time:
+		if x == nil {
+			return dt, fmt.Errorf("nil time")
+		}
+		return *x, nil
+	case string:
+		if x == "now" {
+			dt = tf.clock.Now()
+		} else {
+			dt, err = parseDateTimeIn(x, loc)
+		}
+	case float64:
+		dt, err = parseDateTimeIn(strconv.FormatFloat(x, 'f', -1, 64), loc)
+	case float32:
+		dt, err = parseDateTimeIn(strconv.FormatFloat(float64(x), 'f', -1, 32), loc)
+	case json.Number:
+		dt, err = parseDateTimeIn(x.String(), loc)
+	default:
+		// Integers of any kind (including uint64) are read like their digits
+		if numbers.IsNumber(v) {
+			dt, err = parseDateTimeIn(fmt.Sprintf("%v", v), loc)
+		} else {
+			err = fmt.Errorf("can't convert %T to a time", v)
+		}
+	}
+	if err != nil {
+		return dt, err
+	}
+	if tf.loc != nil {
+		dt = dt.In(tf.loc)
+	}
+	return dt, nil
+}
+
+// relativeTo renders v relative to ref, an empty lang uses the
+// FuncMap's RelativeStrings. An unknown lang or unparsable date renders
+// as an empty string.
+func (tf *timeFuncs) relativeTo(v, ref interface{}, granularity string, lang string) string {
+	t1, t2, ok := tf.toTimes(v, ref)
+	if ok == false {
+		return ""
+	}
+	rs := tf.relative
+	if lang != "" {
+		if rs, ok = LookupRelativeStrings(lang); ok == false {
+			return ""
+		}
+	}
+	return HumanizeRelative(t1, t2, granularity, rs)
+}
+
+// lookupLocale finds the first locale named or the FuncMap's locale
+func (tf *timeFuncs) lookupLocale(names []string) (*Locale, bool) {
+	name := tf.locale
+	if len(names) > 0 {
+		name = names[0]
+	}
+	if name == "" {
+		name = "en"
+	}
+	return LookupLocale(name)
+}
+
+// startMonth returns the first of months if given otherwise fallback
+func (tf *timeFuncs) startMonth(fallback time.Month, months []int) time.Month {
+	if len(months) > 0 && months[0] >= 1 && months[0] <= 12 {
+		return time.Month(months[0])
+	}
+	return fallback
+}
+
+// toTimes converts a pair of values, ok is false if either isn't a time
+func (tf *timeFuncs) toTimes(a, b interface{}) (time.Time, time.Time, bool) {
+	t1, err := tf.toTime(a)
+	if err != nil {
+		return t1, t1, false
+	}
+	t2, err := tf.toTime(b)
+	if err != nil {
+		return t1, t2, false
+	}
+	return t1, t2, true
+}
+
+// format renders v with layout or returns an empty string if v is not a time
+func (tf *timeFuncs) format(v interface{}, layout string) string {
+	dt, err := tf.toTime(v)
+	if err != nil {
+		return ""
+	}
+	return dt.Format(layout)
+}
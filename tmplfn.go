@@ -32,7 +32,7 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
-	"time"
+	"unicode/utf8"
 
 	// Caltech Library Packages
 	"github.com/caltechlibrary/dotpath"
@@ -41,162 +41,21 @@ import (
 
 var (
 
-	// Time provides a common set of time/date related functions for use in text/template or html/template
-	Time = template.FuncMap{
-		"year": func(s string) string {
-			var (
-				dt  time.Time
-				err error
-			)
-			if s == "now" {
-				dt = time.Now()
-			} else {
-				dt, err = time.Parse("2006-01-02", normalizeDate(s))
-				if err != nil {
-					return ""
-				}
-			}
-			return dt.Format("2006")
-		},
-		// timefmt normalizes and formates a datetime string, s, using fmt
-		"timefmt": func(s string, fmt string) string {
-			var (
-				dt  time.Time
-				err error
-			)
-			if s == "now" {
-				dt = time.Now()
-			} else {
-				dt, err = time.Parse("2006-01-02", normalizeDate(s))
-				if err != nil {
-					return ""
-				}
-			}
-			return dt.Format(fmt)
-		},
-		"rfc3339": func(s string) string {
-			var (
-				dt  time.Time
-				err error
-			)
-			if s == "now" {
-				dt = time.Now()
-			} else {
-				dt, err = time.Parse("2006-01-02", normalizeDate(s))
-				if err != nil {
-					return ""
-				}
-			}
-			return dt.Format(time.RFC3339)
-		},
-		"rfc1123": func(s string) string {
-			var (
-				dt  time.Time
-				err error
-			)
-			if s == "now" {
-				dt = time.Now()
-			} else {
-				dt, err = time.Parse("2006-01-02", normalizeDate(s))
-				if err != nil {
-					return ""
-				}
-			}
-			return dt.Format(time.RFC1123)
-		},
-		"rfc1123z": func(s string) string {
-			var (
-				dt  time.Time
-				err error
-			)
-			if s == "now" {
-				dt = time.Now()
-			} else {
-				dt, err = time.Parse("2006-01-02", normalizeDate(s))
-				if err != nil {
-					return ""
-				}
-			}
-			return dt.Format(time.RFC1123Z)
-		},
-		"rfc822z": func(s string) string {
-			var (
-				dt  time.Time
-				err error
-			)
-			if s == "now" {
-				dt = time.Now()
-			} else {
-				dt, err = time.Parse("2006-01-02", normalizeDate(s))
-				if err != nil {
-					return ""
-				}
-			}
-			return dt.Format(time.RFC822Z)
-		},
-		"rfc822": func(s string) string {
-			var (
-				dt  time.Time
-				err error
-			)
-			if s == "now" {
-				dt = time.Now()
-			} else {
-				dt, err = time.Parse("2006-01-02", normalizeDate(s))
-				if err != nil {
-					return ""
-				}
-			}
-			return dt.Format(time.RFC822)
-		},
-		// datefmt FIXME: this is ugly, depreciate in favor of timefmt or rename to something appropriate
-		"datefmt": func(dt, outputFmtYMD, outputFmtYM, outputFmtY string) string {
-			var (
-				inputFmt  string
-				outputFmt string
-			)
-			// NOTE: Date input formats can be YYYY, YYYY-MM and YYYY,
-			// we need to define output formats for each
-			switch {
-			case len(dt) == 4:
-				inputFmt = "2006"
-				outputFmt = outputFmtY
-			case len(dt) > 4 && len(dt) <= 7:
-				inputFmt = "2006-01"
-				outputFmt = outputFmtYM
-			default:
-				inputFmt = "2006-01-02"
-				outputFmt = outputFmtYMD
-			}
-			//intputFmt: 2006-01-02
-			//outputfmt: Jan _2, 2006
-			d, err := time.Parse(inputFmt, dt)
-			if err != nil {
-				return fmt.Sprintf("%s, %s", dt, err.Error())
-			}
-			return d.Format(outputFmt)
-		},
-	}
+	// Time provides a common set of time/date related functions for use in text/template or html/template.
+	// Use TimeFuncMap to render in a specific time zone.
+	Time = TimeFuncMap(nil)
 
-	Math = template.FuncMap{
-		"int":      numbers.Int,
-		"int64":    numbers.Int64,
-		"float32":  numbers.Float32,
-		"float64":  numbers.Float64,
-		"add":      numbers.Add,
-		"sub":      numbers.Subtract,
-		"multiply": numbers.Multiply,
-		"divide":   numbers.Divide,
-		"modulo":   numbers.Modulo,
-		"addi":     numbers.Addi,
-		"subi":     numbers.Subtract,
-		"typeof": func(t interface{}) string {
-			if t == nil {
-				return "<nil>"
-			}
-			return fmt.Sprintf("%T", t)
-		},
-	}
+	// Math provides arithmetic, rounding, comparison, aggregate and
+	// decimal functions. The arithmetic functions return an error for
+	// values that aren't numbers, division by zero, integer overflow
+	// and NaN or infinite results, use LenientMath or MathFuncMap for
+	// the original behavior.
+	Math = MathFuncMap(nil)
+
+	// LenientMath is Math with values that aren't numbers treated as
+	// zero, division by zero returning zero and integer overflow and
+	// NaN results passed through as they were originally
+	LenientMath = MathFuncMap(&MathOptions{Lenient: true, Overflow: numbers.OverflowWrap, NaN: numbers.NaNAllow})
 
 	Strings = template.FuncMap{
 		// concat concatenates strings together
@@ -235,6 +94,34 @@ var (
 		"splitN": func(s string, delimiter string, count int) []string {
 			return strings.SplitN(s, delimiter, count)
 		},
+		// rune_length counts the characters of a string rather than its bytes
+		"rune_length": utf8.RuneCountInString,
+		// truncate shortens a string to at most n characters including the
+		// ellipsis (default "…") without cutting a word in half, e.g.
+		// truncate .abstract 200 or truncate .abstract 200 "..."
+		"truncate": func(s string, n int, ellipsis ...string) string {
+			return Truncate(s, n, optionalEllipsis(ellipsis))
+		},
+		// truncate_words shortens a string to its first n words
+		"truncate_words": func(s string, n int, ellipsis ...string) string {
+			return TruncateWords(s, n, optionalEllipsis(ellipsis))
+		},
+		// truncate_html and truncate_words_html work on HTML fragments,
+		// tags aren't counted and elements left open are closed
+		"truncate_html": func(s string, n int, ellipsis ...string) string {
+			return TruncateHTML(s, n, optionalEllipsis(ellipsis))
+		},
+		"truncate_words_html": func(s string, n int, ellipsis ...string) string {
+			return TruncateWordsHTML(s, n, optionalEllipsis(ellipsis))
+		},
+		// wordwrap breaks lines at width characters, an optional indent
+		// starts each line, e.g. wordwrap .description 72 "    "
+		"wordwrap": func(s string, width int, indent ...string) string {
+			if len(indent) > 0 {
+				return WordWrap(s, width, indent[0])
+			}
+			return WordWrap(s, width, "")
+		},
 	}
 
 	Page = template.FuncMap{
@@ -322,8 +209,10 @@ var (
 		},
 	}
 
-	// Iterables produces lists that then can supply the template range function with values
-	Iterables = template.FuncMap{
+	// Iterables produces lists that then can supply the template range function with values,
+	// dates and month_grid read dates like the Time functions, use TimeFuncMap to set their
+	// Clock and Location
+	Iterables = Join(template.FuncMap{
 		// ints returns an array of int. Both start and end are inclusive. If start <= end the ascending by inc else descending by inc
 		"ints": func(start, end, inc int) []int {
 			var result []int
@@ -441,7 +330,7 @@ var (
 			}
 			return 0
 		},
-	}
+	}, defaultTimeFuncs.calendarFuncs())
 
 	//Booleans provides a set of functions working with Boolean data
 	Booleans = template.FuncMap{
@@ -590,7 +479,7 @@ func Join(maps ...template.FuncMap) template.FuncMap {
 
 // AllFuncs() returns a Join of func maps available in tmplfn
 func AllFuncs() template.FuncMap {
-	return Join(Booleans, Console, Dotpath, Iterables, Math, Page, Path, Strings, Time, Url, RegExp, TextTools)
+	return Join(Booleans, Console, Dotpath, EDTF, Iterables, Math, NumberFormat, Page, Path, Stats, Strings, Time, Url, RegExp, TextTools)
 }
 
 // Src is a mapping of template source to names and byte arrays.
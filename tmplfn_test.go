@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
 	"testing"
 	"text/template"
+
+	// Caltech Library Packages
+	"github.com/caltechlibrary/tmplfn/numbers"
 )
 
 // assembleString like Tmpl.Assemble but using a string as a source for the template
@@ -152,11 +156,22 @@ func TestMathIntFunc(t *testing.T) {
 	}
 	for k, v := range tMap {
 		expected, _ := strconv.Atoi(k)
-		fn := Math["int"].(func(interface{}) int)
-		result := fn(v)
+		fn := Math["int"].(func(interface{}) (int, error))
+		result, err := fn(v)
+		if err != nil {
+			t.Errorf("unexpected error %s", err)
+		}
 		if expected != result {
 			t.Errorf("expected %d, got %T %v", expected, result, result)
 		}
+		lenientFn := LenientMath["int"].(func(interface{}) int)
+		if result = lenientFn(v); expected != result {
+			t.Errorf("expected %d, got %T %v", expected, result, result)
+		}
+	}
+	fn := Math["int"].(func(interface{}) (int, error))
+	if _, err := fn("n/a"); err == nil {
+		t.Errorf("expected an error for n/a")
 	}
 }
 
@@ -433,3 +448,301 @@ func TestNormalizeDate(t *testing.T) {
 		t.Errorf("Expected 2017-08-01, got %q", s)
 	}
 }
+
+func TestMathDecimalFuncs(t *testing.T) {
+	data := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(`{"price": 0.1, "tax": 0.2}`), &data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	tmpl, err := assembleString(Math, `{{ dadd .price .tax }} {{ dround (ddiv 10 3) 2 }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "0.3 3.33"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestMathNumericStrings(t *testing.T) {
+	data := map[string]interface{}{"count": "41", "total": "1,024.5", "note": "n/a"}
+	tmpl, err := assembleString(Math, `{{ add .count "1" }} {{ sub .total 24 }} {{ is_numeric .total }} {{ is_numeric .note }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "42 1000.5 true false"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestMathAggregates(t *testing.T) {
+	data := map[string]interface{}{}
+	src := []byte(`{"records": [{"citations": 4}, {"citations": "10"}, {"title": "no citations"}, {"citations": 1}], "scores": [2, 8, 5]}`)
+	if err := json.Unmarshal(src, &data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	tmpl, err := assembleString(Math, `{{ sum .records ".citations" }} {{ count .records "citations" }} {{ mean .scores }} {{ median .scores }} {{ minimum .records ".citations" }} {{ maximum .scores }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "15 3 5 5 1 8"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestStatsFuncs(t *testing.T) {
+	data := map[string]interface{}{}
+	src := []byte(`{"records": [{"downloads": 2}, {"downloads": 4}, {"downloads": 4}, {"downloads": 4}, {"downloads": 5}, {"downloads": 5}, {"downloads": 7}, {"downloads": 9}]}`)
+	if err := json.Unmarshal(src, &data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	tmpl, err := assembleString(Stats, `{{ stddev .records ".downloads" }} {{ percentile .records 50 ".downloads" }} {{ range histogram .records 2 ".downloads" }}[{{ .Lower }},{{ .Upper }}):{{ .Count }} {{ end }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "2 4.5 [2,5.5):6 [5.5,9):2 "; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+	for _, src := range []string{`{{ histogram .records 1000000000 }}`, `{{ quantiles .records "n/a" }}`, `{{ quantile_histogram .records 10001 }}`} {
+		tmpl, err := assembleString(Stats, src)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if err := tmpl.Execute(bytes.NewBuffer([]byte{}), data); err == nil {
+			t.Errorf("%s, expected Execute to fail", src)
+		}
+	}
+}
+
+func TestNumberFormatFuncs(t *testing.T) {
+	data := map[string]interface{}{"total": 1234567.891, "share": 0.125}
+	src := `{{ format_number .total 2 }} {{ format_currency_compact .total "USD" }} {{ format_percent .share }}`
+	testSet := map[string]string{
+		"en":    "1,234,567.89 $1.2M 12.5%",
+		"de-DE": "1.234.567,89 1,2\u00a0Mio.\u00a0$ 12,5\u00a0%",
+		"tlh":   "1,234,567.89 $1.2M 12.5%",
+	}
+	for locale, expected := range testSet {
+		tmpl, err := assembleString(NumberFormatFuncMap(locale), src)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		buf := bytes.NewBuffer([]byte{})
+		if err := tmpl.Execute(buf, data); err != nil {
+			t.Fatalf("%s", err)
+		}
+		if buf.String() != expected {
+			t.Errorf("%s, expected %q, got %q", locale, expected, buf.String())
+		}
+	}
+}
+
+func TestMathRounding(t *testing.T) {
+	tmpl, err := assembleString(Math, `{{ round 2.5 }} {{ round 2.5 0 "bankers" }} {{ round 2.675 2 }} {{ floor 2.7 }} {{ ceil 2.1 }} {{ abs -4 }} {{ pow 2 8 }} {{ sqrt 2.25 }} {{ min 3 1.5 }} {{ max 1 2 3 }} {{ clamp 11 0 10 }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, nil); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "3 2 2.68 2 3 4 256 1.5 1.5 3 10"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	// Lenient rounding falls back to half_up and clamps the scale
+	tmpl, err = assembleString(LenientMath, `{{ round 2.5 0 "nope" }} {{ round 2.5 1000000000 }} {{ len (printf "%s" (dround 2.5 1000000000)) }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf.Reset()
+	if err := tmpl.Execute(buf, nil); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := fmt.Sprintf("3 2.5 %d", numbers.MaxDecimalExponent+2); buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestMathCalc(t *testing.T) {
+	data := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(`{"price": 2.5, "qty": 4, "shipping": 3, "order": {"discount": 0.1}}`), &data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	tmpl, err := assembleString(Math, `{{ calc "price * qty + shipping / 2" . }} {{ calc "round(price * qty * (1 - order.discount), 2)" . }} {{ calc "(1 + 2) * 3" }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "11.5 9 9"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+	tmpl, _ = assembleString(Math, `{{ calc "price / 0" . }}`)
+	if err := tmpl.Execute(bytes.NewBuffer([]byte{}), data); err == nil {
+		t.Errorf("expected a division by zero error")
+	}
+}
+
+func TestMathErrors(t *testing.T) {
+	data := map[string]interface{}{"count": "n/a", "total": 10, "zero": 0, "big": uint64(math.MaxUint64)}
+	testSet := map[string]string{
+		`{{ add .count 1 }}`:          `"n/a" (string) is not a number`,
+		`{{ divide .total .zero }}`:   "division by zero",
+		`{{ modulo .total 0 }}`:       "division by zero",
+		`{{ multiply .missing 2 }}`:   "missing value",
+		`{{ sqrt -4 }}`:               "negative",
+		`{{ round .total 1 "nope" }}`: "unknown rounding mode",
+		`{{ ddiv .total .zero }}`:     "division by zero",
+		`{{ int .big }}`:              "out of range",
+		`{{ round 2.5 1000000000 }}`:  "round: scale 1000000000 out of range",
+		`{{ round 2.5 0 "nope" }}`:    "round: unknown rounding mode",
+		`{{ dround 2.5 -100000 }}`:    "dround: scale -100000 out of range",
+		`{{ dround 2.5 0 "nope" }}`:   "dround: unknown rounding mode",
+		`{{ int64 1e30 }}`:            "out of range",
+	}
+	for src, expected := range testSet {
+		tmpl, err := assembleString(Math, src)
+		if err != nil {
+			t.Fatalf("%s, %s", src, err)
+		}
+		err = tmpl.Execute(bytes.NewBuffer([]byte{}), data)
+		if err == nil || strings.Contains(err.Error(), expected) == false {
+			t.Errorf("%s, expected an error containing %q, got %v", src, expected, err)
+		}
+	}
+
+	tmpl, err := assembleString(LenientMath, `{{ add .count 1 }} {{ divide .total .zero }} {{ modulo 7.5 2 }} {{ modulo 5 0 }} {{ modulo 5 "0" }} {{ subi 7 2 | typeof }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "1 0 0 0 0 int"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestMathPolicies(t *testing.T) {
+	data := map[string]interface{}{"big": int64(9223372036854775807)}
+	src := `{{ add .big 1 }} {{ pow -8 0.5 }}`
+	testSet := []struct {
+		opts     *MathOptions
+		expected string
+	}{
+		{&MathOptions{Overflow: numbers.OverflowFloat, NaN: numbers.NaNPlaceholder, Placeholder: "n/a"}, "9.223372036854776e+18 n/a"},
+		{&MathOptions{Overflow: numbers.OverflowBig, NaN: numbers.NaNZero, Lenient: true}, "9223372036854775808 0"},
+		{&MathOptions{Overflow: numbers.OverflowWrap, NaN: numbers.NaNAllow, Lenient: true}, "-9223372036854775808 NaN"},
+	}
+	for i, test := range testSet {
+		tmpl, err := assembleString(MathFuncMap(test.opts), src)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		buf := bytes.NewBuffer([]byte{})
+		if err := tmpl.Execute(buf, data); err != nil {
+			t.Errorf("(%d) unexpected error %s", i, err)
+		} else if buf.String() != test.expected {
+			t.Errorf("(%d) expected %q, got %q", i, test.expected, buf.String())
+		}
+	}
+
+	tmpl, err := assembleString(Math, `{{ add .big 1 }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err := tmpl.Execute(bytes.NewBuffer([]byte{}), data); err == nil || strings.Contains(err.Error(), "overflow") == false {
+		t.Errorf("expected an overflow error, got %v", err)
+	}
+	// sum follows the overflow policy like add
+	list := map[string]interface{}{"list": []interface{}{int64(9223372036854775807), 1}}
+	tmpl, _ = assembleString(Math, `{{ sum .list }}`)
+	if err := tmpl.Execute(bytes.NewBuffer([]byte{}), list); err == nil {
+		t.Errorf("expected sum to overflow")
+	}
+	tmpl, _ = assembleString(MathFuncMap(&MathOptions{Overflow: numbers.OverflowBig}), `{{ sum .list }} {{ add (sum .list) 1 }}`)
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, list); err != nil || buf.String() != "9223372036854775808 9223372036854775809" {
+		t.Errorf("expected exact sums, got %q, %v", buf.String(), err)
+	}
+	tmpl, _ = assembleString(MathFuncMap(&MathOptions{NaN: numbers.NaNPlaceholder, Placeholder: "n/a"}), `{{ multiply 1e308 10 }}`)
+	buf = bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, nil); err != nil || buf.String() != "n/a" {
+		t.Errorf("expected n/a, got %q, %v", buf.String(), err)
+	}
+}
+
+func TestNumberFormatBytes(t *testing.T) {
+	data := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(`{"size": 1500000, "label": "2 GiB"}`), &data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	tmpl, err := assembleString(NumberFormat, `{{ format_bytes .size }} {{ format_bytes_iec .size 2 }} {{ parse_bytes .label }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "1.5 MB 1.43 MiB 2147483648"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestMathWords(t *testing.T) {
+	data := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(`{"edition": 2, "volume": 14, "series": "21"}`), &data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	src := `{{ ordinal .edition }} edition, Volume {{ roman .volume }}, {{ spell .series }} ({{ parse_roman "xiv" }})`
+	for _, fm := range []template.FuncMap{Math, LenientMath} {
+		tmpl, err := assembleString(fm, src)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		buf := bytes.NewBuffer([]byte{})
+		if err := tmpl.Execute(buf, data); err != nil {
+			t.Fatalf("%s", err)
+		}
+		if expected := "2nd edition, Volume XIV, twenty-one (14)"; buf.String() != expected {
+			t.Errorf("expected %q, got %q", expected, buf.String())
+		}
+	}
+	tmpl, _ := assembleString(Math, `{{ roman 4000 }}`)
+	if err := tmpl.Execute(bytes.NewBuffer([]byte{}), nil); err == nil {
+		t.Errorf("expected an error for roman 4000")
+	}
+
+	// The lenient functions render something rather than fail
+	tmpl, err := assembleString(LenientMath, `{{ roman 0 }} {{ ordinal "n/a" }} {{ spell 1e30 }} {{ parse_roman "IIII" }} {{ calc "1/0" }} {{ calc "1 +" }}`)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, nil); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if expected := "0 n/a 1e+30 0 0 0"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,400 @@
+package tmplfn
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// DefaultEllipsis is added by the truncate template functions when
+// no ellipsis is given
+var DefaultEllipsis = "…"
+
+var (
+	reEntity = regexp.MustCompile(`^&(?:#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+
+	// voidElements are the HTML elements that have no end tag
+	voidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true,
+		"hr": true, "img": true, "input": true, "link": true, "meta": true,
+		"source": true, "track": true, "wbr": true,
+	}
+
+	// blockElements are the HTML elements that separate words, e.g.
+	// <li>one</li><li>two</li> is two words
+	blockElements = map[string]bool{
+		"address": true, "article": true, "aside": true, "blockquote": true,
+		"br": true, "dd": true, "div": true, "dl": true, "dt": true,
+		"figcaption": true, "figure": true, "footer": true, "h1": true,
+		"h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"header": true, "hr": true, "li": true, "ol": true, "p": true,
+		"pre": true, "section": true, "table": true, "td": true, "th": true,
+		"tr": true, "ul": true,
+	}
+)
+
+// optionalEllipsis returns the first of values or DefaultEllipsis
+func optionalEllipsis(values []string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return DefaultEllipsis
+}
+
+// unit kinds, text units are one character (grapheme cluster or HTML
+// entity) and tags take no space
+const (
+	textUnit = iota
+	openTag
+	closeTag
+	otherTag
+)
+
+type unit struct {
+	kind int
+	text string
+	// name is the lower case element name of an open or close tag
+	name string
+}
+
+// isSpace is true for a text unit holding white space
+func (u unit) isSpace() bool {
+	return u.kind == textUnit && strings.TrimSpace(u.text) == ""
+}
+
+// isBreak is true for white space and block element tags
+func (u unit) isBreak() bool {
+	return u.isSpace() || (u.kind != textUnit && blockElements[u.name])
+}
+
+// isWord is true for a text unit that isn't white space
+func (u unit) isWord() bool {
+	return u.kind == textUnit && strings.TrimSpace(u.text) != ""
+}
+
+// isExtend is true for runes that join the rune before them in a
+// grapheme cluster, combining marks, zero width joiners, variation
+// selectors, emoji modifiers and tag characters
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == '\u200d' ||
+		(r >= 0xfe00 && r <= 0xfe0f) ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) ||
+		(r >= 0xe0020 && r <= 0xe007f)
+}
+
+// isRegionalIndicator is true for the letters that pair up into flags
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// graphemes splits s into the characters a reader sees. It follows the
+// common Unicode grapheme cluster rules (CR LF, combining marks, ZWJ
+// emoji sequences and flags) rather than the full algorithm.
+func graphemes(s string) []string {
+	var clusters []string
+	start, flags := 0, 0
+	prev := rune(-1)
+	for i, r := range s {
+		if i > start {
+			join := (prev == '\r' && r == '\n') ||
+				prev == '\u200d' ||
+				(isExtend(r) && prev != '\n' && prev != '\r') ||
+				(isRegionalIndicator(prev) && isRegionalIndicator(r) && flags%2 == 1)
+			if join == false {
+				clusters = append(clusters, s[start:i])
+				start, flags = i, 0
+			}
+		}
+		if isRegionalIndicator(r) {
+			flags++
+		}
+		prev = r
+	}
+	if start < len(s) {
+		clusters = append(clusters, s[start:])
+	}
+	return clusters
+}
+
+// graphemeLength returns the number of characters a reader sees in s
+func graphemeLength(s string) int {
+	return len(graphemes(s))
+}
+
+// textUnits returns the characters of s as text units
+func textUnits(s string) []unit {
+	var units []unit
+	for _, g := range graphemes(s) {
+		units = append(units, unit{kind: textUnit, text: g})
+	}
+	return units
+}
+
+// tagEnd returns the index of the ">" ending the tag at the start of s,
+// skipping over quoted attribute values, or -1 if the tag isn't closed
+func tagEnd(s string) int {
+	quote, prev := byte(0), byte(0)
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && prev == '=':
+			quote = c
+		case c == '>':
+			return i
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			prev = c
+		}
+	}
+	return -1
+}
+
+// parseTag returns the unit for the tag at the start of s and its
+// length or false if s doesn't start with a tag
+func parseTag(s string) (unit, int, bool) {
+	if len(s) < 3 || s[0] != '<' {
+		return unit{}, 0, false
+	}
+	if strings.HasPrefix(s, "<!--") {
+		if end := strings.Index(s, "-->"); end >= 0 {
+			return unit{kind: otherTag, text: s[0 : end+3]}, end + 3, true
+		}
+		return unit{}, 0, false
+	}
+	c := s[1]
+	if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && c != '/' && c != '!' && c != '?' {
+		return unit{}, 0, false
+	}
+	end := tagEnd(s)
+	if end < 0 {
+		return unit{}, 0, false
+	}
+	tag := s[0 : end+1]
+	u := unit{kind: otherTag, text: tag}
+	if c == '!' || c == '?' {
+		return u, len(tag), true
+	}
+	name := strings.TrimPrefix(tag[1:len(tag)-1], "/")
+	if i := strings.IndexFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '/'
+	}); i >= 0 {
+		name = name[0:i]
+	}
+	u.name = strings.ToLower(name)
+	switch {
+	case c == '/':
+		u.kind = closeTag
+	case strings.HasSuffix(tag, "/>") || voidElements[u.name]:
+		u.kind = otherTag
+	default:
+		u.kind = openTag
+	}
+	return u, len(tag), true
+}
+
+// htmlUnits returns the characters and tags of the HTML fragment s,
+// an entity like &amp; is one character
+func htmlUnits(s string) []unit {
+	var units []unit
+	for len(s) > 0 {
+		i := strings.IndexAny(s, "<&")
+		if i < 0 {
+			return append(units, textUnits(s)...)
+		}
+		units = append(units, textUnits(s[0:i])...)
+		s = s[i:]
+		if u, size, ok := parseTag(s); ok {
+			units = append(units, u)
+			s = s[size:]
+		} else if entity := reEntity.FindString(s); entity != "" {
+			units = append(units, unit{kind: textUnit, text: entity})
+			s = s[len(entity):]
+		} else {
+			units = append(units, unit{kind: textUnit, text: s[0:1]})
+			s = s[1:]
+		}
+	}
+	return units
+}
+
+// splitUnits returns the units of s, tags and entities are only
+// recognized when html is true
+func splitUnits(s string, html bool) []unit {
+	if html {
+		return htmlUnits(s)
+	}
+	return textUnits(s)
+}
+
+// finish returns units[0:cut] followed by ellipsis and end tags for any
+// elements left open. Trailing white space, commas, semicolons, colons
+// and empty elements are dropped before the ellipsis.
+func finish(units []unit, cut int, ellipsis string) string {
+	for cut > 0 {
+		u := units[cut-1]
+		if u.kind == openTag || (u.kind == textUnit && strings.TrimRightFunc(u.text, func(r rune) bool {
+			return unicode.IsSpace(r) || r == ',' || r == ';' || r == ':'
+		}) == "") {
+			cut--
+			continue
+		}
+		break
+	}
+	var (
+		sb   strings.Builder
+		open []string
+	)
+	for _, u := range units[0:cut] {
+		sb.WriteString(u.text)
+		switch u.kind {
+		case openTag:
+			open = append(open, u.name)
+		case closeTag:
+			// close the innermost matching element, stray end tags are ignored
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == u.name {
+					open = open[0:i]
+					break
+				}
+			}
+		}
+	}
+	sb.WriteString(ellipsis)
+	for i := len(open) - 1; i >= 0; i-- {
+		sb.WriteString("</" + open[i] + ">")
+	}
+	return sb.String()
+}
+
+// truncate shortens s to at most n characters including the ellipsis
+func truncate(s string, n int, ellipsis string, html bool) string {
+	units := splitUnits(s, html)
+	visible := 0
+	for _, u := range units {
+		if u.kind == textUnit {
+			visible++
+		}
+	}
+	if visible <= n {
+		return s
+	}
+	// An ellipsis that doesn't fit is left off
+	if graphemeLength(ellipsis) > n {
+		ellipsis = ""
+	}
+	keep := n - graphemeLength(ellipsis)
+	if keep < 0 {
+		keep = 0
+	}
+	cut := 0
+	for count := 0; cut < len(units) && count < keep; cut++ {
+		if units[cut].kind == textUnit {
+			count++
+		}
+	}
+	// Back up to the start of a word cut in half if an earlier word
+	// fits, a single long word is cut where it is
+	midWord := keep > 0 && units[cut-1].isWord()
+	next := cut
+	for next < len(units) && units[next].kind != textUnit {
+		midWord = midWord && units[next].isBreak() == false
+		next++
+	}
+	if midWord && next < len(units) && units[next].isWord() {
+		space, words := -1, false
+		for i := 0; i < cut; i++ {
+			switch {
+			case units[i].isBreak() && words:
+				space = i
+			case units[i].isWord():
+				words = true
+			}
+		}
+		if space > 0 {
+			cut = space
+		}
+	}
+	return finish(units, cut, ellipsis)
+}
+
+// Truncate shortens s to at most n characters, including ellipsis, without
+// cutting a word in half unless it is the only word. Characters are
+// grapheme clusters so accented letters and emoji are never split. If
+// the ellipsis is longer than n it is left off.
+func Truncate(s string, n int, ellipsis string) string {
+	return truncate(s, n, ellipsis, false)
+}
+
+// TruncateHTML is Truncate for an HTML fragment, tags aren't counted,
+// an entity counts as one character and elements left open by the cut
+// are closed after the ellipsis
+func TruncateHTML(s string, n int, ellipsis string) string {
+	return truncate(s, n, ellipsis, true)
+}
+
+// truncateWords shortens s to its first n words followed by ellipsis
+func truncateWords(s string, n int, ellipsis string, html bool) string {
+	units := splitUnits(s, html)
+	cut, words, inWord := 0, 0, false
+	for i, u := range units {
+		switch {
+		case u.isBreak():
+			inWord = false
+		case u.isWord():
+			if inWord == false {
+				words, inWord = words+1, true
+				if words > n {
+					return finish(units, cut, ellipsis)
+				}
+			}
+			cut = i + 1
+		}
+	}
+	return s
+}
+
+// TruncateWords shortens s to its first n words followed by ellipsis,
+// s is returned as is if it has n words or fewer
+func TruncateWords(s string, n int, ellipsis string) string {
+	return truncateWords(s, n, ellipsis, false)
+}
+
+// TruncateWordsHTML is TruncateWords for an HTML fragment, elements
+// left open by the cut are closed after the ellipsis
+func TruncateWordsHTML(s string, n int, ellipsis string) string {
+	return truncateWords(s, n, ellipsis, true)
+}
+
+// WordWrap breaks the lines of s between words so they are at most
+// width characters including indent, which starts each line. Existing
+// line breaks are kept, runs of spaces become one space and a word
+// longer than width gets a line of its own. A width less than one only
+// indents.
+func WordWrap(s string, width int, indent string) string {
+	var lines []string
+	indentLength := graphemeLength(indent)
+	for _, line := range strings.Split(s, "\n") {
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		current, length := indent+words[0], indentLength+graphemeLength(words[0])
+		for _, word := range words[1:] {
+			wordLength := graphemeLength(word)
+			if width > 0 && length+1+wordLength > width {
+				lines = append(lines, current)
+				current, length = indent+word, indentLength+wordLength
+				continue
+			}
+			current, length = current+" "+word, length+1+wordLength
+		}
+		lines = append(lines, current)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,134 @@
+package tmplfn
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGraphemes(t *testing.T) {
+	testSet := map[string]int{
+		"":                     0,
+		"abc":                  3,
+		"e\u0301te\u0301":      3,
+		"été":                  3,
+		"\U0001F44D\U0001F3FD": 1,
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467": 1,
+		"\U0001F1FA\U0001F1F8\U0001F1EB\U0001F1F7":   2,
+		"a\r\nb": 3,
+		"日本語":    3,
+	}
+	for s, expected := range testSet {
+		if r := graphemeLength(s); r != expected {
+			t.Errorf("%q, expected %d, got %d", s, expected, r)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	testSet := []struct {
+		s        string
+		n        int
+		ellipsis string
+		expected string
+	}{
+		{"The quick brown fox", 40, "…", "The quick brown fox"},
+		{"The quick brown fox", 12, "…", "The quick…"},
+		{"The quick brown fox", 11, "...", "The..."},
+		{"The quick, brown fox", 13, "…", "The quick…"},
+		{"Supercalifragilistic", 6, "…", "Super…"},
+		{"Café crème brûlée", 10, "…", "Café…"},
+		{"Café crème brûlée", 11, "…", "Café crème…"},
+		{"résumé writing", 7, "", "résumé"},
+		{"日本語のテキスト", 4, "…", "日本語…"},
+		{"\U0001F44D\U0001F3FD\U0001F44D\U0001F3FD\U0001F44D\U0001F3FD", 2, "…", "\U0001F44D\U0001F3FD…"},
+		{"abc", 0, "…", ""},
+		{"abcdef", 1, "...", "a"},
+		{"abcdef", 3, "...", "..."},
+		{"The quick brown fox", 2, "...", "Th"},
+	}
+	for i, test := range testSet {
+		if r := Truncate(test.s, test.n, test.ellipsis); r != test.expected {
+			t.Errorf("(%d) expected %q, got %q", i, test.expected, r)
+		}
+	}
+}
+
+func TestTruncateHTML(t *testing.T) {
+	testSet := []struct {
+		s        string
+		n        int
+		expected string
+	}{
+		{"<p>Short &amp; sweet</p>", 20, "<p>Short &amp; sweet</p>"},
+		{"<p>Hello <em>brave new</em> world</p>", 13, "<p>Hello <em>brave…</em></p>"},
+		{"<p>Tom &amp; Jerry <b>forever</b></p>", 10, "<p>Tom &amp;…</p>"},
+		{"<p>One<br/>two <img src=\"x.png\"> three four</p>", 12, "<p>One<br/>two <img src=\"x.png\">…</p>"},
+		{"<div><p>First</p><p>Second paragraph</p></div>", 14, "<div><p>First</p><p>Second…</p></div>"},
+		{"<p>A <a href=\"#\">link</a> and 1 < 2 comparisons</p>", 14, "<p>A <a href=\"#\">link</a> and 1…</p>"},
+		{"<a title=\"a>b\">hello world</a>", 5, "<a title=\"a>b\">hell…</a>"},
+		{"<p class='x>y'>one two three</p>", 9, "<p class='x>y'>one two…</p>"},
+		{"<a title=it's>Tom's cat</a>", 7, "<a title=it's>Tom's…</a>"},
+		{"<p><b>abcdef</b></p>", 0, ""},
+		{"<p><b>abcdef</b> ghi</p>", 1, "…"},
+	}
+	for i, test := range testSet {
+		if r := TruncateHTML(test.s, test.n, "…"); r != test.expected {
+			t.Errorf("(%d) expected %q, got %q", i, test.expected, r)
+		}
+	}
+}
+
+func TestTruncateWords(t *testing.T) {
+	testSet := []struct {
+		r, expected string
+	}{
+		{TruncateWords("one two three four", 2, "…"), "one two…"},
+		{TruncateWords("one two three", 3, "…"), "one two three"},
+		{TruncateWords("  one,  two;   three", 2, " [more]"), "  one,  two [more]"},
+		{TruncateWords("naïve café au lait", 2, "…"), "naïve café…"},
+		{TruncateWordsHTML("<p>one <b>two three</b> four</p>", 2, "…"), "<p>one <b>two…</b></p>"},
+		{TruncateWordsHTML("<ul><li>one</li><li>two</li></ul>", 1, "…"), "<ul><li>one…</li></ul>"},
+	}
+	for i, test := range testSet {
+		if test.r != test.expected {
+			t.Errorf("(%d) expected %q, got %q", i, test.expected, test.r)
+		}
+	}
+}
+
+func TestWordWrap(t *testing.T) {
+	testSet := []struct {
+		r, expected string
+	}{
+		{WordWrap("The quick brown fox jumps over the lazy dog", 15, ""), "The quick brown\nfox jumps over\nthe lazy dog"},
+		{WordWrap("The quick brown fox", 12, "  "), "  The quick\n  brown fox"},
+		{WordWrap("Crème brûlée très délicieuse", 12, ""), "Crème brûlée\ntrès\ndélicieuse"},
+		{WordWrap("see https://example.org/a/very/long/path now", 10, ""), "see\nhttps://example.org/a/very/long/path\nnow"},
+		{WordWrap("one\n\ntwo   three", 0, "> "), "> one\n\n> two three"},
+	}
+	for i, test := range testSet {
+		if test.r != test.expected {
+			t.Errorf("(%d) expected %q, got %q", i, test.expected, test.r)
+		}
+	}
+}
+
+func TestStringsTruncate(t *testing.T) {
+	data := map[string]interface{}{
+		"abstract": "Ünïcödé text is counted by character",
+		"html":     "<p>Some <i>emphasized</i> text</p>",
+	}
+	src := `{{ rune_length .abstract }} {{ length .abstract }}|{{ truncate .abstract 20 }}|{{ truncate .abstract 20 "..." }}|{{ truncate_words .abstract 2 }}|{{ truncate_html .html 9 }}|{{ truncate_words_html .html 2 "" }}|{{ wordwrap .abstract 16 "- " }}`
+	tmpl, err := assembleString(Join(Strings, Iterables), src)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buf, data); err != nil {
+		t.Fatalf("%s", err)
+	}
+	expected := "36 40|Ünïcödé text is…|Ünïcödé text is...|Ünïcödé text…|<p>Some…</p>|<p>Some <i>emphasized</i></p>|- Ünïcödé text\n- is counted by\n- character"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}